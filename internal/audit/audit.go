@@ -16,14 +16,24 @@ type EventType string
 
 const (
 	// Secret operations
-	EventSecretList    EventType = "SECRET_LIST"
-	EventSecretAccess  EventType = "SECRET_ACCESS"
-	EventSecretReveal  EventType = "SECRET_REVEAL"
-	EventSecretCopy    EventType = "SECRET_COPY"
-	EventSecretCreate  EventType = "SECRET_CREATE"
-	EventSecretDelete  EventType = "SECRET_DELETE"
-	EventVersionAdd    EventType = "VERSION_ADD"
-	EventVersionList   EventType = "VERSION_LIST"
+	EventSecretList        EventType = "SECRET_LIST"
+	EventSecretAccess      EventType = "SECRET_ACCESS"
+	EventSecretReveal      EventType = "SECRET_REVEAL"
+	EventSecretCopy        EventType = "SECRET_COPY"
+	EventSecretCreate      EventType = "SECRET_CREATE"
+	EventSecretDelete      EventType = "SECRET_DELETE"
+	EventVersionAdd        EventType = "VERSION_ADD"
+	EventVersionList       EventType = "VERSION_LIST"
+	EventVersionDisable    EventType = "VERSION_DISABLE"
+	EventVersionEnable     EventType = "VERSION_ENABLE"
+	EventVersionDestroy    EventType = "VERSION_DESTROY"
+	EventSecretCompare     EventType = "SECRET_COMPARE"
+	EventLabelsUpdate      EventType = "LABELS_UPDATE"
+	EventAnnotationsUpdate EventType = "ANNOTATIONS_UPDATE"
+	EventExpiryUpdate      EventType = "EXPIRY_UPDATE"
+	EventRotationUpdate    EventType = "ROTATION_UPDATE"
+	EventAliasUpdate       EventType = "ALIAS_UPDATE"
+	EventIAMUpdate         EventType = "IAM_UPDATE"
 
 	// Configuration operations
 	EventConfigChange  EventType = "CONFIG_CHANGE"
@@ -53,6 +63,7 @@ type Event struct {
 	EventType  EventType         `json:"event_type"`
 	Result     EventResult       `json:"result"`
 	User       string            `json:"user,omitempty"`
+	Principal  string            `json:"principal,omitempty"` // Identity the call was authorized as, e.g. an impersonated service account
 	ProjectID  string            `json:"project_id,omitempty"`
 	SecretName string            `json:"secret_name,omitempty"`
 	Version    string            `json:"version,omitempty"`
@@ -69,6 +80,8 @@ type Logger struct {
 	maxSizeMB  int
 	maxAgeDays int
 	userEmail  string
+	principal  string
+	base       *Logger // Logger writing the file, set on loggers made by WithIdentity
 }
 
 // Config holds audit logger configuration
@@ -171,6 +184,17 @@ func NewLogger(cfg Config) (*Logger, error) {
 
 // Log writes an audit event
 func (l *Logger) Log(event Event) error {
+	if l.base != nil {
+		l.mu.Lock()
+		if event.User == "" {
+			event.User = l.userEmail
+		}
+		if event.Principal == "" {
+			event.Principal = l.principal
+		}
+		l.mu.Unlock()
+		return l.base.Log(event)
+	}
 	if !l.enabled || l.file == nil {
 		return nil
 	}
@@ -187,6 +211,9 @@ func (l *Logger) Log(event Event) error {
 	if event.User == "" && l.userEmail != "" {
 		event.User = l.userEmail
 	}
+	if event.Principal == "" && l.principal != "" {
+		event.Principal = l.principal
+	}
 
 	// Serialize event to JSON
 	data, err := json.Marshal(event)
@@ -283,6 +310,132 @@ func (l *Logger) LogVersionAdd(projectID, secretName, version string, result Eve
 	})
 }
 
+// LogVersionDisable logs a version disable event
+func (l *Logger) LogVersionDisable(projectID, secretName, version string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventVersionDisable,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Version:    version,
+		Error:      errMsg,
+	})
+}
+
+// LogVersionEnable logs a version enable event
+func (l *Logger) LogVersionEnable(projectID, secretName, version string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventVersionEnable,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Version:    version,
+		Error:      errMsg,
+	})
+}
+
+// LogVersionDestroy logs a version destroy event
+func (l *Logger) LogVersionDestroy(projectID, secretName, version string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventVersionDestroy,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Version:    version,
+		Error:      errMsg,
+	})
+}
+
+// LogSecretCompare logs a payload comparison of a secret between two projects
+func (l *Logger) LogSecretCompare(projectID, otherProjectID, secretName, outcome string, result EventResult, errMsg string) {
+	details := map[string]string{"compared_with": otherProjectID}
+	if outcome != "" {
+		details["payload"] = outcome
+	}
+	_ = l.Log(Event{
+		EventType:  EventSecretCompare,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    details,
+		Error:      errMsg,
+	})
+}
+
+// LogLabelsUpdate logs a label change of a secret with the old and new label sets
+func (l *Logger) LogLabelsUpdate(projectID, secretName, oldLabels, newLabels string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventLabelsUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"old_labels": oldLabels, "new_labels": newLabels},
+		Error:      errMsg,
+	})
+}
+
+// LogAnnotationsUpdate logs a change to the annotations of a secret
+func (l *Logger) LogAnnotationsUpdate(projectID, secretName, oldAnnotations, newAnnotations string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventAnnotationsUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"old_annotations": oldAnnotations, "new_annotations": newAnnotations},
+		Error:      errMsg,
+	})
+}
+
+// LogExpiryUpdate logs a change to the expiration time of a secret
+func (l *Logger) LogExpiryUpdate(projectID, secretName, oldExpiry, newExpiry string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventExpiryUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"old_expire_time": oldExpiry, "new_expire_time": newExpiry},
+		Error:      errMsg,
+	})
+}
+
+// LogRotationUpdate logs a change to the rotation schedule or topics of a secret
+func (l *Logger) LogRotationUpdate(projectID, secretName, oldRotation, newRotation string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventRotationUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"old_rotation": oldRotation, "new_rotation": newRotation},
+		Error:      errMsg,
+	})
+}
+
+// LogAliasUpdate logs assigning, moving or removing a version alias of a
+// secret. An empty version means the alias did not exist or was removed.
+func (l *Logger) LogAliasUpdate(projectID, secretName, alias, oldVersion, newVersion string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventAliasUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"alias": alias, "old_version": oldVersion, "new_version": newVersion},
+		Error:      errMsg,
+	})
+}
+
+// LogIAMUpdate logs a change to the IAM policy of a secret. changes lists
+// the granted and revoked role members, e.g. "+ roles/x user:a@b.c".
+func (l *Logger) LogIAMUpdate(projectID, secretName, changes string, result EventResult, errMsg string) {
+	_ = l.Log(Event{
+		EventType:  EventIAMUpdate,
+		Result:     result,
+		ProjectID:  projectID,
+		SecretName: secretName,
+		Details:    map[string]string{"changes": changes},
+		Error:      errMsg,
+	})
+}
+
 // LogSecretList logs a secret listing event
 func (l *Logger) LogSecretList(projectID string, count int, result EventResult, errMsg string) {
 	_ = l.Log(Event{
@@ -470,6 +623,36 @@ func (l *Logger) SetUser(userEmail string) {
 	l.userEmail = userEmail
 }
 
+// SetPrincipal sets the identity calls are authorized as, included in all
+// audit events next to the user
+func (l *Logger) SetPrincipal(principal string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.principal = principal
+}
+
+// WithIdentity returns a logger writing to the same file whose events are
+// attributed to user and principal, such as those of a second project opened
+// with other credentials. Closing it leaves the file open.
+func (l *Logger) WithIdentity(user, principal string) *Logger {
+	if l == nil {
+		return nil
+	}
+	base := l
+	if l.base != nil {
+		base = l.base
+	}
+	return &Logger{
+		enabled:    base.enabled,
+		filePath:   base.filePath,
+		maxSizeMB:  base.maxSizeMB,
+		maxAgeDays: base.maxAgeDays,
+		userEmail:  user,
+		principal:  principal,
+		base:       base,
+	}
+}
+
 // GetUser returns the current user email
 func (l *Logger) GetUser() string {
 	return l.userEmail
@@ -0,0 +1,77 @@
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// PlanCopy plans copying the latest enabled version of each source secret
+// from src to dst under the same ID, so regional secrets stay in their
+// location. Missing secrets are created with the labels, replication and KMS
+// keys of the source, so the target project needs access to those keys. The
+// expiration time is not copied: the source's may be close or already past,
+// so the plan only notes it. Existing secrets get a new version; in sync mode
+// only when their latest enabled version differs. Every read is audited in
+// the project it happens in.
+func PlanCopy(ctx context.Context, src, dst gcp.SecretStore, logger *audit.Logger,
+	sources []gcp.Secret, dstSecrets []gcp.Secret, sync bool) (*Plan, error) {
+	existing := make(map[string]bool, len(dstSecrets))
+	for _, secret := range dstSecrets {
+		existing[secret.ID()] = true
+	}
+
+	plan := &Plan{}
+	for _, secret := range sources {
+		item := PlanItem{
+			Key:         secret.ID(),
+			Secret:      secret.ID(),
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+			Replicas:    secret.Replicas,
+			KMSKeyName:  secret.KMSKeyName,
+		}
+
+		entries, skipped, err := Fetch(ctx, src, logger, []string{secret.ID()})
+		if err != nil {
+			plan.Zero()
+			return nil, err
+		}
+		if len(skipped) > 0 {
+			item.Action = ActionSkip
+			item.Note = "no enabled version in source"
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+		item.Value = entries[0].Value
+
+		switch {
+		case !existing[secret.ID()]:
+			item.Action = ActionCreate
+			if !secret.ExpireTime.IsZero() {
+				item.Note = expiryNote(secret.ExpireTime, time.Now())
+			}
+		case sync:
+			if err := compareLatest(ctx, dst, logger, &item); err != nil {
+				plan.Items = append(plan.Items, item)
+				plan.Zero()
+				return nil, err
+			}
+		default:
+			item.Action = ActionAddVersion
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, nil
+}
+
+// expiryNote tells that a created copy does not keep the source's expiration
+func expiryNote(expire, now time.Time) string {
+	if !expire.After(now) {
+		return "source has expired, copy never expires"
+	}
+	return fmt.Sprintf("source expires in %s, copy never expires", gcp.FormatRemaining(expire, now))
+}
@@ -0,0 +1,181 @@
+package bulk
+
+import (
+	"context"
+	"crypto/sha256"
+	"sort"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// PayloadState is the result of comparing the latest payloads of a secret
+type PayloadState string
+
+const (
+	PayloadNotCompared PayloadState = ""
+	PayloadSame        PayloadState = "same"
+	PayloadDifferent   PayloadState = "different"
+	PayloadMissing     PayloadState = "no enabled version" // In at least one project
+)
+
+// LabelChange is a label whose value differs between two projects
+type LabelChange struct {
+	Key string
+	A   string // Value in A, empty when InA is false
+	B   string // Value in B, empty when InB is false
+	InA bool
+	InB bool
+}
+
+// String renders the change as key=a→b, using "-" for a missing label
+func (c LabelChange) String() string {
+	a, b := "-", "-"
+	if c.InA {
+		a = c.A
+	}
+	if c.InB {
+		b = c.B
+	}
+	return c.Key + "=" + a + "→" + b
+}
+
+// DiffEntry describes a secret present in both projects
+type DiffEntry struct {
+	Name    string
+	Labels  []LabelChange
+	Payload PayloadState
+}
+
+// Diff compares the secret inventories of two projects
+type Diff struct {
+	ProjectA string
+	ProjectB string
+	OnlyA    []string
+	OnlyB    []string
+	Both     []DiffEntry
+}
+
+// DiffInventories compares two secret lists by ID and labels, so a regional
+// secret only matches the secret of the same name in the same location.
+// Only secrets whose name starts with prefix are considered.
+func DiffInventories(projectA string, a []gcp.Secret, projectB string, b []gcp.Secret, prefix string) *Diff {
+	byName := func(secrets []gcp.Secret) map[string]gcp.Secret {
+		m := make(map[string]gcp.Secret, len(secrets))
+		for _, secret := range secrets {
+			if strings.HasPrefix(secret.Name, prefix) {
+				m[secret.ID()] = secret
+			}
+		}
+		return m
+	}
+	inA, inB := byName(a), byName(b)
+
+	d := &Diff{ProjectA: projectA, ProjectB: projectB}
+	for name, secretA := range inA {
+		secretB, ok := inB[name]
+		if !ok {
+			d.OnlyA = append(d.OnlyA, name)
+			continue
+		}
+		d.Both = append(d.Both, DiffEntry{Name: name, Labels: diffLabels(secretA.Labels, secretB.Labels)})
+	}
+	for name := range inB {
+		if _, ok := inA[name]; !ok {
+			d.OnlyB = append(d.OnlyB, name)
+		}
+	}
+
+	sort.Strings(d.OnlyA)
+	sort.Strings(d.OnlyB)
+	sort.Slice(d.Both, func(i, j int) bool {
+		return d.Both[i].Name < d.Both[j].Name
+	})
+	return d
+}
+
+func diffLabels(a, b map[string]string) []LabelChange {
+	var changes []LabelChange
+	for k, va := range a {
+		vb, ok := b[k]
+		if !ok || va != vb {
+			changes = append(changes, LabelChange{Key: k, A: va, B: vb, InA: true, InB: ok})
+		}
+	}
+	for k, vb := range b {
+		if _, ok := a[k]; !ok {
+			changes = append(changes, LabelChange{Key: k, B: vb, InB: true})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// InSync reports whether both projects hold the same secrets with the same
+// labels and, where compared, the same payloads
+func (d *Diff) InSync() bool {
+	if len(d.OnlyA) > 0 || len(d.OnlyB) > 0 {
+		return false
+	}
+	for _, e := range d.Both {
+		if len(e.Labels) > 0 || (e.Payload != PayloadNotCompared && e.Payload != PayloadSame) {
+			return false
+		}
+	}
+	return true
+}
+
+// ComparePayloads hashes the latest enabled version of every secret present
+// in both projects and records whether they match. Values never leave this
+// function; each read is audited as SECRET_ACCESS and each comparison as
+// SECRET_COMPARE.
+func (d *Diff) ComparePayloads(ctx context.Context, a, b gcp.SecretStore, logger *audit.Logger) error {
+	for i := range d.Both {
+		e := &d.Both[i]
+		hashA, okA, err := latestHash(ctx, a, logger, e.Name)
+		if err != nil {
+			if logger != nil {
+				logger.LogSecretCompare(d.ProjectA, d.ProjectB, e.Name, "", audit.ResultFailure, err.Error())
+			}
+			return err
+		}
+		hashB, okB, err := latestHash(ctx, b, logger, e.Name)
+		if err != nil {
+			if logger != nil {
+				logger.LogSecretCompare(d.ProjectA, d.ProjectB, e.Name, "", audit.ResultFailure, err.Error())
+			}
+			return err
+		}
+
+		switch {
+		case !okA || !okB:
+			e.Payload = PayloadMissing
+		case hashA == hashB:
+			e.Payload = PayloadSame
+		default:
+			e.Payload = PayloadDifferent
+		}
+		if logger != nil {
+			logger.LogSecretCompare(d.ProjectA, d.ProjectB, e.Name, string(e.Payload), audit.ResultSuccess, "")
+		}
+	}
+	return nil
+}
+
+// latestHash returns the SHA-256 of the latest enabled version of a secret.
+// ok is false when the secret has no enabled version.
+func latestHash(ctx context.Context, store gcp.SecretStore, logger *audit.Logger, name string) (hash [sha256.Size]byte, ok bool, err error) {
+	entries, skipped, err := Fetch(ctx, store, logger, []string{name})
+	if err != nil {
+		return hash, false, err
+	}
+	if len(skipped) > 0 {
+		return hash, false, nil
+	}
+	hash = sha256.Sum256(entries[0].Value)
+	ZeroEntries(entries)
+	return hash, true, nil
+}
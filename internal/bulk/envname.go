@@ -0,0 +1,112 @@
+package bulk
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// NamingRule selects which part of a secret name becomes the variable name
+type NamingRule string
+
+const (
+	// NamingRelative uses the path below the selected folder (app/prod/db/pass -> DB_PASS)
+	NamingRelative NamingRule = "relative"
+	// NamingLeaf uses only the last path segment (app/prod/db/pass -> PASS)
+	NamingLeaf NamingRule = "leaf"
+	// NamingFull uses the whole secret name (app/prod/db/pass -> APP_PROD_DB_PASS)
+	NamingFull NamingRule = "full"
+)
+
+// ParseNamingRule validates a naming rule, defaulting to NamingRelative
+func ParseNamingRule(s string) (NamingRule, error) {
+	switch rule := NamingRule(s); rule {
+	case "":
+		return NamingRelative, nil
+	case NamingRelative, NamingLeaf, NamingFull:
+		return rule, nil
+	default:
+		return "", fmt.Errorf("unknown naming rule %q (expected relative, leaf or full)", s)
+	}
+}
+
+// EnvNaming derives environment variable names from secret names
+type EnvNaming struct {
+	Folder    string     // Selected folder, used by NamingRelative
+	Separator string     // Folder separator
+	Rule      NamingRule // Which part of the name to use
+	Prefix    string     // Prepended to every name
+}
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// Name returns the variable name for a secret: the selected part of the name,
+// upper-cased, with separators and other invalid characters turned into underscores
+func (n EnvNaming) Name(secretName string) string {
+	name := secretName
+	switch n.Rule {
+	case NamingLeaf:
+		if i := strings.LastIndex(name, n.Separator); i >= 0 && n.Separator != "" {
+			name = name[i+len(n.Separator):]
+		}
+	case NamingFull:
+	default:
+		name = strings.TrimPrefix(name, FolderPrefix(n.Folder, n.Separator))
+	}
+
+	name = invalidEnvChars.ReplaceAllString(strings.ToUpper(n.Prefix+name), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// CollisionError reports secrets that map to the same variable name
+type CollisionError struct {
+	Collisions map[string][]string // Variable name -> secret IDs
+}
+
+func (e *CollisionError) Error() string {
+	vars := make([]string, 0, len(e.Collisions))
+	for v := range e.Collisions {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+
+	parts := make([]string, 0, len(vars))
+	for _, v := range vars {
+		parts = append(parts, fmt.Sprintf("%s <- %s", v, strings.Join(e.Collisions[v], ", ")))
+	}
+	return "environment variable name collision: " + strings.Join(parts, "; ")
+}
+
+// AssignEnvNames maps each secret ID to the variable name of its secret name.
+// Names in skip are left out, so explicit mappings can take precedence.
+// Two secrets deriving the same name are reported as a *CollisionError.
+func (n EnvNaming) AssignEnvNames(secretIDs []string, skip map[string]bool) (map[string]string, error) {
+	bySecret := make(map[string]string, len(secretIDs))
+	byVar := make(map[string][]string, len(secretIDs))
+	for _, secret := range secretIDs {
+		name, _ := gcp.ParseSecretID(secret)
+		v := n.Name(name)
+		if skip[v] {
+			continue
+		}
+		bySecret[secret] = v
+		byVar[v] = append(byVar[v], secret)
+	}
+
+	collisions := make(map[string][]string)
+	for v, secrets := range byVar {
+		if len(secrets) > 1 {
+			collisions[v] = secrets
+		}
+	}
+	if len(collisions) > 0 {
+		return nil, &CollisionError{Collisions: collisions}
+	}
+	return bySecret, nil
+}
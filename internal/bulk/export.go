@@ -0,0 +1,53 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"github.com/theburrowhub/go-secret/internal/secretfile"
+)
+
+// ExportResult summarizes an export
+type ExportResult struct {
+	Exported int      // Secrets written
+	Skipped  []string // Secrets without an enabled version
+}
+
+// Export reads the latest enabled version of every secret under the naming
+// folder and encodes them in format, keyed by their derived variable names.
+// Key collisions are reported before any secret is read. The caller owns the
+// returned buffer and should zero it once written.
+func Export(ctx context.Context, store gcp.SecretStore, logger *audit.Logger, secrets []gcp.Secret,
+	naming EnvNaming, format secretfile.Format, opts secretfile.ManifestOptions) ([]byte, ExportResult, error) {
+	selected := UnderFolder(secrets, naming.Folder, naming.Separator)
+	if len(selected) == 0 {
+		return nil, ExportResult{}, fmt.Errorf("no secrets under folder %s", naming.Folder)
+	}
+	ids := make([]string, 0, len(selected))
+	for _, secret := range selected {
+		ids = append(ids, secret.ID())
+	}
+	keys, err := naming.AssignEnvNames(ids, nil)
+	if err != nil {
+		return nil, ExportResult{}, err
+	}
+
+	entries, skipped, err := Fetch(ctx, store, logger, ids)
+	if err != nil {
+		return nil, ExportResult{}, err
+	}
+	defer ZeroEntries(entries)
+
+	pairs := make([]secretfile.Pair, 0, len(entries))
+	for _, e := range entries {
+		pairs = append(pairs, secretfile.Pair{Key: keys[e.Name], Value: e.Value})
+	}
+	var buf bytes.Buffer
+	if err := secretfile.Encode(&buf, format, pairs, opts); err != nil {
+		return nil, ExportResult{}, fmt.Errorf("failed to encode secrets: %w", err)
+	}
+	return buf.Bytes(), ExportResult{Exported: len(entries), Skipped: skipped}, nil
+}
@@ -0,0 +1,84 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// ErrNoEnabledVersion is returned when every version of a secret is disabled or destroyed
+var ErrNoEnabledVersion = errors.New("no enabled version")
+
+// Entry is the value of one secret read by Fetch
+type Entry struct {
+	Name    string // Secret name
+	Version string // Version that was read
+	Value   []byte
+}
+
+// ZeroEntries wipes the values of entries once they are no longer needed
+func ZeroEntries(entries []Entry) {
+	for _, e := range entries {
+		for i := range e.Value {
+			e.Value[i] = 0
+		}
+	}
+}
+
+// LatestEnabledVersion returns the newest enabled version of a secret
+func LatestEnabledVersion(ctx context.Context, store gcp.SecretStore, name string) (string, error) {
+	versions, err := store.ListSecretVersions(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	// Versions are listed newest first
+	for _, v := range versions {
+		if v.State == "ENABLED" {
+			return v.Name, nil
+		}
+	}
+	return "", ErrNoEnabledVersion
+}
+
+// Fetch reads the latest enabled version of every named secret, auditing
+// each read as SECRET_ACCESS. Secrets without an enabled version are
+// returned in skipped; any other error aborts and wipes what was read.
+func Fetch(ctx context.Context, store gcp.SecretStore, logger *audit.Logger, names []string) (entries []Entry, skipped []string, err error) {
+	projectID := store.ProjectID()
+	logger = storeLogger(logger, store)
+	for _, name := range names {
+		version, err := LatestEnabledVersion(ctx, store, name)
+		if errors.Is(err, ErrNoEnabledVersion) {
+			skipped = append(skipped, name)
+			continue
+		}
+		if err != nil {
+			ZeroEntries(entries)
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		value, err := store.AccessSecretVersion(ctx, name, version)
+		if err != nil {
+			if logger != nil {
+				logger.LogSecretAccess(projectID, name, version, audit.ResultFailure, err.Error())
+			}
+			ZeroEntries(entries)
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if logger != nil {
+			logger.LogSecretAccess(projectID, name, version, audit.ResultSuccess, "")
+		}
+		entries = append(entries, Entry{Name: name, Version: version, Value: value})
+	}
+	return entries, skipped, nil
+}
+
+// storeLogger attributes the events about store to the identity it was opened
+// with, which differs from the session's when another project has its own
+// credentials
+func storeLogger(logger *audit.Logger, store gcp.SecretStore) *audit.Logger {
+	return logger.WithIdentity(store.UserEmail(), store.Principal())
+}
@@ -0,0 +1,35 @@
+// Package bulk implements operations on every secret under a virtual folder,
+// shared by the interactive UI and the command line.
+package bulk
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// FolderPrefix normalizes a folder path to a name prefix ending in sep.
+// An empty folder selects the whole project.
+func FolderPrefix(folder, sep string) string {
+	folder = strings.TrimPrefix(folder, sep)
+	if folder == "" || strings.HasSuffix(folder, sep) {
+		return folder
+	}
+	return folder + sep
+}
+
+// UnderFolder returns the secrets whose name lies under folder, sorted by name
+func UnderFolder(secrets []gcp.Secret, folder, sep string) []gcp.Secret {
+	prefix := FolderPrefix(folder, sep)
+	var out []gcp.Secret
+	for _, secret := range secrets {
+		if strings.HasPrefix(secret.Name, prefix) && len(secret.Name) > len(prefix) {
+			out = append(out, secret)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,252 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"github.com/theburrowhub/go-secret/internal/secretfile"
+)
+
+// Action is what an import or copy does with one secret
+type Action int
+
+const (
+	ActionCreate     Action = iota // Secret does not exist yet
+	ActionAddVersion               // Secret exists, a new version is added
+	ActionUnchanged                // Latest enabled version already holds the value
+	ActionSkip                     // Nothing can be written, see PlanItem.Note
+)
+
+// String returns a short description for plan output
+func (a Action) String() string {
+	switch a {
+	case ActionCreate:
+		return "create"
+	case ActionAddVersion:
+		return "new version"
+	case ActionUnchanged:
+		return "unchanged"
+	default:
+		return "skip"
+	}
+}
+
+// PlanItem is the planned change for one secret
+type PlanItem struct {
+	Key         string // Key in the imported file or source secret name
+	Secret      string // Target secret ID
+	Action      Action
+	Note        string // Why the action was chosen, e.g. "value differs"
+	Value       []byte
+	Labels      map[string]string // Labels of a created secret
+	Annotations map[string]string // Annotations of a created secret
+	Replicas    []gcp.Replica     // Replication of a created secret
+	KMSKeyName  string            // Customer-managed key of a created secret without replicas
+}
+
+// Describe returns the action with its note for plan output
+func (item PlanItem) Describe() string {
+	if item.Note == "" {
+		return item.Action.String()
+	}
+	return fmt.Sprintf("%s (%s)", item.Action, item.Note)
+}
+
+// Plan is the set of changes an import or copy would make
+type Plan struct {
+	Items []PlanItem
+}
+
+// Count returns how many items have the given action
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, item := range p.Items {
+		if item.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges reports whether applying the plan would write anything
+func (p *Plan) HasChanges() bool {
+	return p.Count(ActionCreate)+p.Count(ActionAddVersion) > 0
+}
+
+// Zero wipes the planned values
+func (p *Plan) Zero() {
+	for _, item := range p.Items {
+		for i := range item.Value {
+			item.Value[i] = 0
+		}
+	}
+}
+
+// ImportOptions controls how file keys map to secrets
+type ImportOptions struct {
+	Prefix     string            // Folder the keys are imported into
+	Separator  string            // Folder separator
+	Labels     map[string]string // Labels for new secrets
+	Replicas   []gcp.Replica     // Replication for new secrets, empty for automatic
+	KMSKeyName string            // Customer-managed key for new secrets with automatic replication
+}
+
+// PlanImport compares pairs against the existing secrets of the project.
+// A key matches the secret prefix+key or, so that exported files import back
+// in place, the secret under prefix whose relative variable name equals the
+// key (DB_PASSWORD -> prefix+db/password). Unmatched keys become prefix+key.
+// The prefix is a folder, so a missing trailing separator is added.
+// Existing secrets are compared with their latest enabled version, and every
+// such read is audited.
+func PlanImport(ctx context.Context, store gcp.SecretStore, logger *audit.Logger, secrets []gcp.Secret,
+	pairs []secretfile.Pair, opts ImportOptions) (*Plan, error) {
+	existing := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		existing[secret.ID()] = true
+	}
+	prefix := FolderPrefix(opts.Prefix, opts.Separator)
+	byEnvName := envNameIndex(secrets, prefix, opts.Separator)
+
+	plan := &Plan{}
+	for _, pair := range pairs {
+		item := PlanItem{
+			Key:        pair.Key,
+			Secret:     prefix + pair.Key,
+			Value:      pair.Value,
+			Labels:     opts.Labels,
+			Replicas:   opts.Replicas,
+			KMSKeyName: opts.KMSKeyName,
+		}
+		if match, ok := byEnvName[pair.Key]; ok && !existing[item.Secret] {
+			item.Secret = match
+		}
+		switch {
+		case len(pair.Value) == 0:
+			item.Action = ActionSkip
+			item.Note = "empty value"
+		case !existing[item.Secret]:
+			item.Action = ActionCreate
+		default:
+			if err := compareLatest(ctx, store, logger, &item); err != nil {
+				return nil, err
+			}
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, nil
+}
+
+// compareLatest sets the action of an item whose secret exists in store:
+// unchanged when its latest enabled version holds item.Value, otherwise a
+// new version. The read is audited.
+func compareLatest(ctx context.Context, store gcp.SecretStore, logger *audit.Logger, item *PlanItem) error {
+	logger = storeLogger(logger, store)
+	item.Action = ActionAddVersion
+	version, err := LatestEnabledVersion(ctx, store, item.Secret)
+	if errors.Is(err, ErrNoEnabledVersion) {
+		item.Note = "no enabled version"
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", item.Secret, err)
+	}
+
+	current, err := store.AccessSecretVersion(ctx, item.Secret, version)
+	if err != nil {
+		if logger != nil {
+			logger.LogSecretAccess(store.ProjectID(), item.Secret, version, audit.ResultFailure, err.Error())
+		}
+		return fmt.Errorf("%s: %w", item.Secret, err)
+	}
+	if logger != nil {
+		logger.LogSecretAccess(store.ProjectID(), item.Secret, version, audit.ResultSuccess, "")
+	}
+	if bytes.Equal(current, item.Value) {
+		item.Action = ActionUnchanged
+	} else {
+		item.Note = "value differs"
+	}
+	for i := range current {
+		current[i] = 0
+	}
+	return nil
+}
+
+// envNameIndex maps the relative variable name of every secret under prefix
+// to its secret ID. Names derived by more than one secret are left out.
+func envNameIndex(secrets []gcp.Secret, prefix, sep string) map[string]string {
+	if prefix == "" || sep == "" {
+		return nil
+	}
+	naming := EnvNaming{Folder: prefix, Separator: sep, Rule: NamingRelative}
+	index := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for _, secret := range UnderFolder(secrets, prefix, sep) {
+		name := naming.Name(secret.Name)
+		if _, dup := index[name]; dup {
+			ambiguous[name] = true
+		}
+		index[name] = secret.ID()
+	}
+	for name := range ambiguous {
+		delete(index, name)
+	}
+	return index
+}
+
+// ApplyResult summarizes an applied plan
+type ApplyResult struct {
+	Created int
+	Updated int
+}
+
+// Apply creates missing secrets and adds versions where the value differs,
+// auditing every write. It stops at the first error; the result tells what
+// was already done.
+func (p *Plan) Apply(ctx context.Context, store gcp.SecretStore, logger *audit.Logger) (ApplyResult, error) {
+	projectID := store.ProjectID()
+	logger = storeLogger(logger, store)
+	var result ApplyResult
+	for _, item := range p.Items {
+		if item.Action != ActionCreate && item.Action != ActionAddVersion {
+			continue
+		}
+
+		if item.Action == ActionCreate {
+			name, location := gcp.ParseSecretID(item.Secret)
+			spec := gcp.Secret{Name: name, Location: location, Labels: item.Labels, Annotations: item.Annotations,
+				Replicas: item.Replicas, KMSKeyName: item.KMSKeyName}
+			if err := store.CreateSecret(ctx, spec); err != nil {
+				if logger != nil {
+					logger.LogSecretCreate(projectID, item.Secret, audit.ResultFailure, err.Error())
+				}
+				return result, fmt.Errorf("%s: %w", item.Secret, err)
+			}
+			if logger != nil {
+				logger.LogSecretCreate(projectID, item.Secret, audit.ResultSuccess, "")
+			}
+		}
+
+		version, err := store.AddSecretVersion(ctx, item.Secret, item.Value)
+		if err != nil {
+			if logger != nil {
+				logger.LogVersionAdd(projectID, item.Secret, "", audit.ResultFailure, err.Error())
+			}
+			return result, fmt.Errorf("%s: %w", item.Secret, err)
+		}
+		if logger != nil {
+			logger.LogVersionAdd(projectID, item.Secret, version.Name, audit.ResultSuccess, "")
+		}
+
+		if item.Action == ActionCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+func runAlias(ctx context.Context, env Env, args []string) error {
+	var projectID string
+	var remove bool
+	fs := newFlagSet(env, "alias", &projectID)
+	fs.BoolVar(&remove, "remove", false, "Remove the alias instead of assigning it")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	var name, alias, version string
+	switch {
+	case remove && len(rest) == 2:
+		name, alias = rest[0], rest[1]
+	case !remove && len(rest) == 3:
+		name, alias, version = rest[0], rest[1], rest[2]
+	default:
+		return usageErrorf("expected a secret name, an alias and a version, or --remove without version")
+	}
+	if err := gcp.ValidateAlias(alias); err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	secret, err := s.store.GetSecret(ctx, name)
+	if err != nil {
+		return err
+	}
+	oldVersion := secret.AliasVersion(alias)
+	if remove && oldVersion == "" {
+		return fmt.Errorf("secret %s has no alias %s", name, alias)
+	}
+	aliases, err := gcp.WithAlias(secret.Aliases, alias, version)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	if err := s.store.UpdateSecretVersionAliases(ctx, name, aliases, secret.Etag); err != nil {
+		s.audit.LogAliasUpdate(s.projectID, name, alias, oldVersion, version, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogAliasUpdate(s.projectID, name, alias, oldVersion, version, audit.ResultSuccess, "")
+	if remove {
+		fmt.Fprintf(env.Stderr, "Removed alias %s of %s\n", alias, name)
+	} else {
+		fmt.Fprintf(env.Stderr, "Alias %s of %s now points at version %s\n", alias, name, version)
+	}
+	return nil
+}
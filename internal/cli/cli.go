@@ -0,0 +1,356 @@
+// Package cli implements the non-interactive go-secrets subcommands,
+// so the same audited tool can be used from scripts and Makefiles.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/config"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// Env holds everything a subcommand needs from the outside world
+type Env struct {
+	Config    *config.Config
+	OpenStore gcp.StoreOpener
+	ProjectID string // Project from the global -project flag, may be empty
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+}
+
+// command is a single subcommand
+type command struct {
+	usage   string
+	summary string
+	run     func(ctx context.Context, env Env, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list": {
+			usage:   "list [-o table|json|names] [--prefix PATH] [--filter EXPR]",
+			summary: "List secrets in the project",
+			run:     runList,
+		},
+		"get": {
+			usage:   "get <name> [--version N|ALIAS]",
+			summary: "Print the value of a secret version to stdout",
+			run:     runGet,
+		},
+		"put": {
+			usage:   "put <name> [--from-file FILE] [--label KEY=VALUE]... [--annotation KEY=VALUE]... [--location REGION]... [--kms-key KEY] [--expires TTL|DATE] [--topic TOPIC]... [--rotation-period P] [--next-rotation TTL|DATE]",
+			summary: "Add a version from a file or stdin, creating the secret if needed",
+			run:     runPut,
+		},
+		"alias": {
+			usage:   "alias <name> <alias> <version> | alias <name> <alias> --remove",
+			summary: "Point a version alias at a version, or remove it",
+			run:     runAlias,
+		},
+		"iam": {
+			usage:   "iam <name> [--grant EMAIL]... [--revoke EMAIL]... [--role ROLE] [--yes]",
+			summary: "Show the IAM policy of a secret, or grant and revoke access to it",
+			run:     runIAM,
+		},
+		"delete": {
+			usage:   "delete <name> [--yes]",
+			summary: "Delete a secret and all its versions",
+			run:     runDelete,
+		},
+		"exec": {
+			usage:   "exec [--map VAR=SECRET[@LOCATION][@VERSION|@ALIAS]]... [--folder PATH [--naming RULE] [--env-prefix P]] [--] COMMAND [ARGS...]",
+			summary: "Run a command with secrets injected as environment variables",
+			run:     runExec,
+		},
+		"diff": {
+			usage:   "diff [--project A] --project B [--prefix PATH] [--filter EXPR] [--compare-values] [-o table|json] [--exit-code]",
+			summary: "Compare the secret inventories of two projects",
+			run:     runDiff,
+		},
+		"expiring": {
+			usage:   "expiring [--within DURATION] [--prefix PATH] [--filter EXPR] [-o table|json] [--exit-code]",
+			summary: "Report secrets that expire soon",
+			run:     runExpiring,
+		},
+		"export": {
+			usage:   "export <folder> [--format dotenv|json|yaml|k8s] [--output FILE] [--naming RULE] [--env-prefix P] [--name NAME] [--namespace NS]",
+			summary: "Write the latest version of every secret under a folder to a file",
+			run:     runExport,
+		},
+		"import": {
+			usage:   "import <file> [--prefix PATH] [--format F] [--dry-run] [--yes] [--label KEY=VALUE]... [--location REGION]... [--kms-key KEY]",
+			summary: "Create or update secrets from a dotenv, JSON, YAML or k8s file",
+			run:     runImport,
+		},
+		"help": {
+			usage:   "help",
+			summary: "Show this help",
+			run: func(ctx context.Context, env Env, args []string) error {
+				PrintUsage(env.Stdout)
+				return nil
+			},
+		},
+	}
+}
+
+// PrintUsage writes the list of subcommands to w
+func PrintUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  go-secrets [-p PROJECT]                   Start the interactive UI")
+	fmt.Fprintln(w, "  go-secrets [-p PROJECT] <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Regional secrets are addressed as <name>@<location>.")
+	fmt.Fprintln(w, "Run 'go-secrets <command> -h' for the flags of a command.")
+}
+
+// usageError is returned for invalid invocations; it exits with status 2
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// exitCodeError carries the exit status of a child process started by exec
+type exitCodeError int
+
+func (e exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// Run executes the subcommand named in args[0] and returns the process exit code
+func Run(ctx context.Context, env Env, args []string) int {
+	if len(args) == 0 {
+		PrintUsage(env.Stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.Stderr, "Error: unknown command %q\n\n", args[0])
+		PrintUsage(env.Stderr)
+		return 2
+	}
+
+	err := cmd.run(ctx, env, args[1:])
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	var exitErr exitCodeError
+	if errors.As(err, &exitErr) {
+		// The child already reported its own failure
+		return int(exitErr)
+	}
+
+	fmt.Fprintf(env.Stderr, "Error: %v\n", err)
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		fmt.Fprintf(env.Stderr, "Usage: go-secrets %s\n", cmd.usage)
+		return 2
+	}
+	return 1
+}
+
+// newFlagSet creates a flag set for a subcommand with the shared -project flag
+func newFlagSet(env Env, name string, projectID *string) *flag.FlagSet {
+	fs := newBareFlagSet(env, name)
+	fs.StringVar(projectID, "project", env.ProjectID, "GCP Project ID")
+	fs.StringVar(projectID, "p", env.ProjectID, "GCP Project ID (shorthand)")
+	return fs
+}
+
+// newBareFlagSet creates a flag set for a subcommand that defines its own project flags
+func newBareFlagSet(env Env, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Usage: go-secrets %s\n\nFlags:\n", commands[name].usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args allowing flags before and after positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// session is an open connection to the secret store plus the audit logger
+type session struct {
+	projectID string
+	store     gcp.SecretStore
+	audit     *audit.Logger
+	locations []string // Locations listed for regional secrets
+}
+
+// openSession connects to the store for projectID (or the configured default)
+func openSession(ctx context.Context, env Env, projectID string) (*session, error) {
+	if projectID == "" {
+		projectID = env.Config.ProjectID
+	}
+	if projectID == "" {
+		return nil, usageErrorf("no project set, use -p PROJECT or configure project_id")
+	}
+
+	store, err := env.OpenStore(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session{
+		projectID: projectID,
+		store:     store,
+		audit:     newAuditLogger(env, store),
+		locations: env.Config.SecretLocations,
+	}, nil
+}
+
+// Close releases the store and flushes the audit log
+func (s *session) Close() {
+	_ = s.store.Close()
+	_ = s.audit.Close()
+}
+
+// newAuditLogger creates the audit logger from config.
+// A logger that fails to open is reported and replaced by a disabled one.
+func newAuditLogger(env Env, store gcp.SecretStore) *audit.Logger {
+	auditCfg := audit.Config{
+		Enabled:    env.Config.Audit.Enabled,
+		FilePath:   env.Config.Audit.FilePath,
+		MaxSizeMB:  env.Config.Audit.MaxSizeMB,
+		MaxAgeDays: env.Config.Audit.MaxAgeDays,
+	}
+	logger, err := audit.NewLogger(auditCfg)
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "Warning: audit logging unavailable: %v\n", err)
+		logger, _ = audit.NewLogger(audit.Config{Enabled: false})
+	}
+	logger.SetUser(store.UserEmail())
+	logger.SetPrincipal(store.Principal())
+	return logger
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// parseKeyValues parses KEY=VALUE pairs into a map
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, usageErrorf("invalid KEY=VALUE pair %q", pair)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// parseLabels parses KEY=VALUE flags and validates them as secret labels
+func parseLabels(pairs []string) (map[string]string, error) {
+	labels, err := parseKeyValues(pairs)
+	if err != nil {
+		return nil, err
+	}
+	if err := gcp.ValidateLabels(labels); err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+	return labels, nil
+}
+
+// parseAnnotations parses KEY=VALUE flags and validates them as secret annotations
+func parseAnnotations(pairs []string) (map[string]string, error) {
+	annotations, err := parseKeyValues(pairs)
+	if err != nil {
+		return nil, err
+	}
+	if err := gcp.ValidateAnnotations(annotations); err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+	return annotations, nil
+}
+
+// isTerminal reports whether v is an *os.File attached to a terminal
+func isTerminal(v any) bool {
+	f, ok := v.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks a yes/no question on the terminal
+func confirm(env Env, question string) bool {
+	fmt.Fprintf(env.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(env.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// zero wipes a secret buffer once it is no longer needed
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// diffJSON is the JSON representation of `diff -o json`
+type diffJSON struct {
+	ProjectA string          `json:"project_a"`
+	ProjectB string          `json:"project_b"`
+	OnlyA    []string        `json:"only_in_a"`
+	OnlyB    []string        `json:"only_in_b"`
+	Both     []diffEntryJSON `json:"in_both"`
+}
+
+type diffEntryJSON struct {
+	Name    string   `json:"name"`
+	Labels  []string `json:"label_changes,omitempty"`
+	Payload string   `json:"payload,omitempty"`
+}
+
+func runDiff(ctx context.Context, env Env, args []string) error {
+	var projects stringList
+	var prefix, filter, output string
+	var compareValues, exitCode bool
+	fs := newBareFlagSet(env, "diff")
+	fs.Var(&projects, "project", "Project to compare (give twice, or once to compare with the default project)")
+	fs.Var(&projects, "p", "Project to compare (shorthand)")
+	fs.StringVar(&prefix, "prefix", "", "Only compare secrets whose name starts with PATH")
+	fs.StringVar(&filter, "filter", "", "Secret Manager filter applied when listing both projects")
+	fs.BoolVar(&compareValues, "compare-values", false, "Compare latest payloads by hash (reads are audited)")
+	fs.StringVar(&output, "output", "table", "Output format: table or json")
+	fs.StringVar(&output, "o", "table", "Output format (shorthand)")
+	fs.BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when the projects differ")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	projects = append(projects, rest...)
+	if output != "table" && output != "json" {
+		return usageErrorf("invalid output format %q", output)
+	}
+
+	var projectA, projectB string
+	switch len(projects) {
+	case 1:
+		projectA, projectB = env.ProjectID, projects[0]
+	case 2:
+		projectA, projectB = projects[0], projects[1]
+	default:
+		return usageErrorf("expected two projects to compare")
+	}
+
+	s, err := openSession(ctx, env, projectA)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if s.projectID == projectB {
+		return usageErrorf("cannot compare project %s with itself", projectB)
+	}
+
+	other, err := env.OpenStore(ctx, projectB)
+	if err != nil {
+		return err
+	}
+	defer other.Close()
+	// Events about the other project carry the identity it was opened with
+	otherAudit := s.audit.WithIdentity(other.UserEmail(), other.Principal())
+
+	secretsA, err := gcp.ListAllSecrets(ctx, s.store, filter, s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretList(s.projectID, len(secretsA), audit.ResultSuccess, "")
+	secretsB, err := gcp.ListAllSecrets(ctx, other, filter, s.locations...)
+	if err != nil {
+		otherAudit.LogSecretList(projectB, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	otherAudit.LogSecretList(projectB, len(secretsB), audit.ResultSuccess, "")
+
+	d := bulk.DiffInventories(s.projectID, secretsA, projectB, secretsB, prefix)
+	if compareValues {
+		if err := d.ComparePayloads(ctx, s.store, other, s.audit); err != nil {
+			return err
+		}
+	}
+
+	if output == "json" {
+		err = writeDiffJSON(env.Stdout, d)
+	} else {
+		err = writeDiffTable(env.Stdout, d)
+	}
+	if err != nil {
+		return err
+	}
+	if exitCode && !d.InSync() {
+		return exitCodeError(1)
+	}
+	return nil
+}
+
+func writeDiffTable(w io.Writer, d *bulk.Diff) error {
+	fmt.Fprintf(w, "Only in %s (%d):\n", d.ProjectA, len(d.OnlyA))
+	for _, name := range d.OnlyA {
+		fmt.Fprintf(w, "  - %s\n", name)
+	}
+	fmt.Fprintf(w, "\nOnly in %s (%d):\n", d.ProjectB, len(d.OnlyB))
+	for _, name := range d.OnlyB {
+		fmt.Fprintf(w, "  + %s\n", name)
+	}
+	fmt.Fprintf(w, "\nIn both (%d):\n", len(d.Both))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, e := range d.Both {
+		labels := "labels same"
+		if len(e.Labels) > 0 {
+			changes := make([]string, 0, len(e.Labels))
+			for _, c := range e.Labels {
+				changes = append(changes, c.String())
+			}
+			labels = "labels " + strings.Join(changes, ",")
+		}
+		payload := ""
+		if e.Payload != bulk.PayloadNotCompared {
+			payload = "payload " + string(e.Payload)
+		}
+		fmt.Fprintf(tw, "  = %s\t%s\t%s\n", e.Name, labels, payload)
+	}
+	return tw.Flush()
+}
+
+func writeDiffJSON(w io.Writer, d *bulk.Diff) error {
+	out := diffJSON{
+		ProjectA: d.ProjectA,
+		ProjectB: d.ProjectB,
+		OnlyA:    append([]string{}, d.OnlyA...),
+		OnlyB:    append([]string{}, d.OnlyB...),
+		Both:     make([]diffEntryJSON, 0, len(d.Both)),
+	}
+	for _, e := range d.Both {
+		entry := diffEntryJSON{Name: e.Name, Payload: string(e.Payload)}
+		for _, c := range e.Labels {
+			entry.Labels = append(entry.Labels, c.String())
+		}
+		out.Both = append(out.Both, entry)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// envVarPattern matches valid environment variable names
+var envVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envMapping maps an environment variable to a secret version
+type envMapping struct {
+	Var     string
+	Secret  string
+	Version string
+}
+
+// parseEnvMapping parses VAR=SECRET[@VERSION], where VERSION is a version
+// number, an alias or latest. SECRET may itself be a regional name@location,
+// so a single suffix naming one of locations is a location rather than a
+// version; name@location@VERSION selects a version of a regional secret.
+func parseEnvMapping(value string, locations []string) (envMapping, error) {
+	name, ref, ok := strings.Cut(value, "=")
+	if !ok || name == "" || ref == "" {
+		return envMapping{}, usageErrorf("invalid mapping %q, expected VAR=SECRET[@VERSION]", value)
+	}
+	if !envVarPattern.MatchString(name) {
+		return envMapping{}, usageErrorf("invalid environment variable name %q", name)
+	}
+
+	secret, version := ref, "latest"
+	switch parts := strings.Split(ref, "@"); {
+	case len(parts) > 3 || parts[0] == "":
+		return envMapping{}, usageErrorf("invalid mapping %q, expected VAR=SECRET[@LOCATION][@VERSION]", value)
+	case len(parts) == 3:
+		secret, version = parts[0]+"@"+parts[1], parts[2]
+	case len(parts) == 2 && !slices.Contains(locations, parts[1]):
+		secret, version = parts[0], parts[1]
+	}
+	if version == "" {
+		return envMapping{}, usageErrorf("invalid mapping %q, empty version", value)
+	}
+	return envMapping{Var: name, Secret: secret, Version: version}, nil
+}
+
+func runExec(ctx context.Context, env Env, args []string) error {
+	var projectID, folder, naming, prefix string
+	var mapFlags stringList
+	fs := newFlagSet(env, "exec", &projectID)
+	fs.Var(&mapFlags, "map", "Inject a secret as VAR=SECRET[@VERSION] (repeatable)")
+	fs.StringVar(&folder, "folder", "", "Inject the latest version of every secret under PATH")
+	fs.StringVar(&naming, "naming", env.Config.Env.Naming, "Variable names for --folder: relative, leaf or full")
+	fs.StringVar(&prefix, "env-prefix", env.Config.Env.Prefix, "Prefix for variable names derived by --folder")
+	// Flags end at "--" or at the command itself, whose own flags are left untouched
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	command := fs.Args()
+	if len(command) == 0 {
+		return usageErrorf("missing command to run")
+	}
+	rule, err := bulk.ParseNamingRule(naming)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	var mappings []envMapping
+	seen := make(map[string]bool)
+	for _, value := range mapFlags {
+		m, err := parseEnvMapping(value, env.Config.SecretLocations)
+		if err != nil {
+			return err
+		}
+		if seen[m.Var] {
+			return usageErrorf("variable %s is mapped more than once", m.Var)
+		}
+		seen[m.Var] = true
+		mappings = append(mappings, m)
+	}
+	if len(mappings) == 0 && folder == "" {
+		return usageErrorf("nothing to inject, use --map or --folder")
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if folder != "" {
+		envNaming := bulk.EnvNaming{
+			Folder:    folder,
+			Separator: env.Config.FolderSeparator,
+			Rule:      rule,
+			Prefix:    prefix,
+		}
+		folderMappings, err := folderEnvMappings(ctx, s, envNaming, seen)
+		if err != nil {
+			return err
+		}
+		mappings = append(folderMappings, mappings...)
+	}
+
+	childEnv, err := resolveEnv(ctx, s, mappings)
+	if err != nil {
+		return err
+	}
+
+	return runChild(env, command, childEnv)
+}
+
+// folderEnvMappings maps the latest version of every secret under the naming
+// folder to a derived variable name. Variables in explicit are skipped so that
+// --map wins over the folder; two secrets deriving the same name are an error.
+func folderEnvMappings(ctx context.Context, s *session, naming bulk.EnvNaming, explicit map[string]bool) ([]envMapping, error) {
+	secrets, err := gcp.ListAllSecrets(ctx, s.store, "", s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return nil, err
+	}
+	s.audit.LogSecretList(s.projectID, len(secrets), audit.ResultSuccess, "")
+
+	selected := bulk.UnderFolder(secrets, naming.Folder, naming.Separator)
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no secrets under folder %s", naming.Folder)
+	}
+	ids := make([]string, 0, len(selected))
+	for _, secret := range selected {
+		ids = append(ids, secret.ID())
+	}
+
+	vars, err := naming.AssignEnvNames(ids, explicit)
+	if err != nil {
+		return nil, err
+	}
+	mappings := make([]envMapping, 0, len(vars))
+	for _, secret := range selected {
+		if v, ok := vars[secret.ID()]; ok {
+			mappings = append(mappings, envMapping{Var: v, Secret: secret.ID(), Version: "latest"})
+		}
+	}
+	return mappings, nil
+}
+
+// resolveEnv accesses every mapped secret and returns the child environment:
+// the current environment with the mapped variables added or replaced.
+// Payload buffers are zeroed as soon as they have been copied into the environment.
+func resolveEnv(ctx context.Context, s *session, mappings []envMapping) ([]string, error) {
+	injected := make(map[string]bool, len(mappings))
+	var vars []string
+	for _, m := range mappings {
+		value, err := s.store.AccessSecretVersion(ctx, m.Secret, m.Version)
+		if err != nil {
+			s.audit.LogSecretAccess(s.projectID, m.Secret, m.Version, audit.ResultFailure, err.Error())
+			return nil, fmt.Errorf("%s: %w", m.Var, err)
+		}
+		s.audit.LogSecretAccess(s.projectID, m.Secret, m.Version, audit.ResultSuccess, "")
+		vars = append(vars, m.Var+"="+string(value))
+		zero(value)
+		injected[m.Var] = true
+	}
+
+	childEnv := make([]string, 0, len(os.Environ())+len(vars))
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if !injected[name] {
+			childEnv = append(childEnv, kv)
+		}
+	}
+	return append(childEnv, vars...), nil
+}
+
+// runChild runs command with the given environment, relaying termination
+// signals to it. A non-zero exit status is returned as an exitCodeError.
+func runChild(env Env, command []string, childEnv []string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Env = childEnv
+	cmd.Stdin = env.Stdin
+	cmd.Stdout = env.Stdout
+	cmd.Stderr = env.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", command[0], err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// Terminated by a signal
+			code = 1
+		}
+		return exitCodeError(code)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// expiringJSON is the JSON representation of a secret in `expiring -o json`
+type expiringJSON struct {
+	Name       string `json:"name"`
+	Location   string `json:"location,omitempty"`
+	ExpireTime string `json:"expire_time"`
+	Remaining  string `json:"remaining"`
+}
+
+func runExpiring(ctx context.Context, env Env, args []string) error {
+	var projectID, within, prefix, filter, output string
+	var exitCode bool
+	fs := newFlagSet(env, "expiring", &projectID)
+	fs.StringVar(&within, "within", "", "Report secrets expiring within DURATION, e.g. 30d (default: expiry.warn_days from config)")
+	fs.StringVar(&prefix, "prefix", "", "Only report secrets whose name starts with PATH")
+	fs.StringVar(&filter, "filter", "", "Secret Manager filter applied by the server, e.g. labels.team=payments")
+	fs.StringVar(&output, "output", "table", "Output format: table or json")
+	fs.StringVar(&output, "o", "table", "Output format (shorthand)")
+	fs.BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when any secret expires within the window")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	if output != "table" && output != "json" {
+		return usageErrorf("invalid output format %q", output)
+	}
+	window := time.Duration(env.Config.Expiry.WarnDays) * 24 * time.Hour
+	if within != "" {
+		if window, err = gcp.ParseTTL(within); err != nil {
+			return usageErrorf("--within: %v", err)
+		}
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	secrets, err := gcp.ListAllSecrets(ctx, s.store, filter, s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretList(s.projectID, len(secrets), audit.ResultSuccess, "")
+
+	now := time.Now()
+	var expiring []gcp.Secret
+	for _, secret := range secrets {
+		if strings.HasPrefix(secret.Name, prefix) && secret.ExpiresWithin(window, now) {
+			expiring = append(expiring, secret)
+		}
+	}
+	// Soonest first
+	sort.Slice(expiring, func(i, j int) bool {
+		if !expiring[i].ExpireTime.Equal(expiring[j].ExpireTime) {
+			return expiring[i].ExpireTime.Before(expiring[j].ExpireTime)
+		}
+		return expiring[i].ID() < expiring[j].ID()
+	})
+
+	if output == "json" {
+		err = writeExpiringJSON(env.Stdout, expiring, now)
+	} else {
+		err = writeExpiringTable(env.Stdout, expiring, now)
+	}
+	if err != nil {
+		return err
+	}
+	if exitCode && len(expiring) > 0 {
+		return exitCodeError(1)
+	}
+	return nil
+}
+
+func writeExpiringJSON(w io.Writer, secrets []gcp.Secret, now time.Time) error {
+	out := make([]expiringJSON, 0, len(secrets))
+	for _, secret := range secrets {
+		out = append(out, expiringJSON{
+			Name:       secret.Name,
+			Location:   secret.Location,
+			ExpireTime: formatExpireTimeJSON(secret.ExpireTime),
+			Remaining:  gcp.FormatRemaining(secret.ExpireTime, now),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeExpiringTable(w io.Writer, secrets []gcp.Secret, now time.Time) error {
+	if len(secrets) == 0 {
+		fmt.Fprintln(w, "No secrets expiring in that window")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tLOCATION\tEXPIRES\tIN")
+	for _, secret := range secrets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", secret.Name, formatLocation(secret.Location),
+			secret.ExpireTime.UTC().Format("2006-01-02 15:04:05"), gcp.FormatRemaining(secret.ExpireTime, now))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"github.com/theburrowhub/go-secret/internal/secretfile"
+)
+
+func runExport(ctx context.Context, env Env, args []string) error {
+	var projectID, formatName, output, naming, prefix, name, namespace string
+	fs := newFlagSet(env, "export", &projectID)
+	fs.StringVar(&formatName, "format", "dotenv", "Output format: dotenv, json, yaml or k8s")
+	fs.StringVar(&formatName, "f", "dotenv", "Output format (shorthand)")
+	fs.StringVar(&output, "output", "", "Write to FILE (created with 0600 permissions)")
+	fs.StringVar(&output, "o", "", "Write to FILE (shorthand)")
+	fs.StringVar(&naming, "naming", env.Config.Env.Naming, "Key names: relative, leaf or full")
+	fs.StringVar(&prefix, "env-prefix", env.Config.Env.Prefix, "Prefix for every key")
+	fs.StringVar(&name, "name", "", "metadata.name of the k8s Secret (default: derived from the folder)")
+	fs.StringVar(&namespace, "namespace", "", "metadata.namespace of the k8s Secret")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one folder")
+	}
+	folder := rest[0]
+	format, err := secretfile.ParseFormat(formatName)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	rule, err := bulk.ParseNamingRule(naming)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	// Never print secrets to an interactive terminal
+	if output == "" && isTerminal(env.Stdout) {
+		return usageErrorf("refusing to write secrets to a terminal, use --output FILE or redirect stdout")
+	}
+	if name == "" {
+		name = secretfile.ManifestName(folder, env.Config.FolderSeparator)
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	secrets, err := gcp.ListAllSecrets(ctx, s.store, "", s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretList(s.projectID, len(secrets), audit.ResultSuccess, "")
+
+	envNaming := bulk.EnvNaming{
+		Folder:    folder,
+		Separator: env.Config.FolderSeparator,
+		Rule:      rule,
+		Prefix:    prefix,
+	}
+	data, result, err := bulk.Export(ctx, s.store, s.audit, secrets, envNaming, format,
+		secretfile.ManifestOptions{Name: name, Namespace: namespace})
+	if err != nil {
+		return err
+	}
+	defer zero(data)
+
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(env.Stderr, "Skipped (no enabled version): %s\n", strings.Join(result.Skipped, ", "))
+	}
+
+	if output == "" {
+		_, err = env.Stdout.Write(data)
+		return err
+	}
+	f, err := secretfile.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Fprintf(env.Stderr, "Exported %d secrets to %s\n", result.Exported, output)
+	return nil
+}
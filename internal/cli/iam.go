@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+func runIAM(ctx context.Context, env Env, args []string) error {
+	var projectID, role string
+	var grants, revokes stringList
+	var yes bool
+	fs := newFlagSet(env, "iam", &projectID)
+	fs.Var(&grants, "grant", "Grant the role to a user or service account email (repeatable)")
+	fs.Var(&revokes, "revoke", "Revoke the role from a user or service account email (repeatable)")
+	fs.StringVar(&role, "role", gcp.SecretAccessorRole, "Role to grant or revoke")
+	fs.BoolVar(&yes, "yes", false, "Do not ask for confirmation")
+	fs.BoolVar(&yes, "y", false, "Do not ask for confirmation (shorthand)")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one secret name")
+	}
+	name := rest[0]
+
+	var grantMembers, revokeMembers []string
+	for _, value := range grants {
+		member, err := gcp.ParseMember(value)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		grantMembers = append(grantMembers, member)
+	}
+	for _, value := range revokes {
+		member, err := gcp.ParseMember(value)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		revokeMembers = append(revokeMembers, member)
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	policy, err := s.store.GetIamPolicy(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(grantMembers) == 0 && len(revokeMembers) == 0 {
+		printPolicy(env, *policy)
+		return nil
+	}
+
+	updated := policy.Clone()
+	for _, member := range grantMembers {
+		updated = updated.WithMember(role, member)
+	}
+	for _, member := range revokeMembers {
+		updated = updated.WithoutMember(role, member)
+	}
+	changes := gcp.DiffIAM(*policy, updated)
+	if len(changes) == 0 {
+		fmt.Fprintf(env.Stderr, "IAM policy of %s is unchanged\n", name)
+		return nil
+	}
+
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = change.String()
+		fmt.Fprintln(env.Stderr, lines[i])
+	}
+	if !yes {
+		if !isTerminal(env.Stdin) {
+			return usageErrorf("refusing to change the IAM policy without confirmation, pass --yes")
+		}
+		if !confirm(env, fmt.Sprintf("Apply these changes to the IAM policy of %s?", name)) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	if err := s.store.SetIamPolicy(ctx, name, updated); err != nil {
+		s.audit.LogIAMUpdate(s.projectID, name, strings.Join(lines, "; "), audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogIAMUpdate(s.projectID, name, strings.Join(lines, "; "), audit.ResultSuccess, "")
+	fmt.Fprintf(env.Stderr, "Updated IAM policy of %s\n", name)
+	return nil
+}
+
+// printPolicy writes the members of each role, one per line
+func printPolicy(env Env, policy gcp.IAMPolicy) {
+	if len(policy.Bindings) == 0 {
+		fmt.Fprintln(env.Stderr, "No IAM bindings on this secret, access is inherited from the project")
+		return
+	}
+	for _, binding := range policy.Bindings {
+		fmt.Fprintln(env.Stdout, binding.Role+conditionSuffix(binding.Condition))
+		for _, member := range binding.Members {
+			fmt.Fprintf(env.Stdout, "  %s\n", member)
+		}
+	}
+}
+
+// conditionSuffix describes the condition of a binding, empty when it has none
+func conditionSuffix(condition *gcp.IAMCondition) string {
+	if condition == nil {
+		return ""
+	}
+	if condition.Title != "" {
+		return fmt.Sprintf(" (if %s)", condition.Title)
+	}
+	return fmt.Sprintf(" (if %s)", condition.Expression)
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"github.com/theburrowhub/go-secret/internal/secretfile"
+)
+
+func runImport(ctx context.Context, env Env, args []string) error {
+	var projectID, prefix, formatName, kmsKey string
+	var dryRun, yes bool
+	var labelPairs, locations stringList
+	fs := newFlagSet(env, "import", &projectID)
+	fs.StringVar(&prefix, "prefix", "", "Prefix for the secret names, e.g. app/dev/")
+	fs.StringVar(&formatName, "format", "", "Input format: dotenv, json, yaml or k8s (default: from the file extension)")
+	fs.StringVar(&formatName, "f", "", "Input format (shorthand)")
+	fs.BoolVar(&dryRun, "dry-run", false, "Only show the plan")
+	fs.BoolVar(&yes, "yes", false, "Apply without asking for confirmation")
+	fs.BoolVar(&yes, "y", false, "Apply without asking for confirmation (shorthand)")
+	fs.Var(&labelPairs, "label", "Label KEY=VALUE for new secrets (repeatable)")
+	fs.Var(&locations, "location", "Replica region for new secrets, repeatable (default: automatic replication)")
+	fs.StringVar(&kmsKey, "kms-key", "", "Cloud KMS key encrypting new secrets (default: the key saved for --location)")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one file")
+	}
+	path := rest[0]
+	labels, err := parseLabels(labelPairs)
+	if err != nil {
+		return err
+	}
+	spec := gcp.Secret{Replicas: gcp.ReplicasIn(locations...)}
+	if err := encryptSpec(env, &spec, kmsKey); err != nil {
+		return err
+	}
+
+	var format secretfile.Format
+	if formatName != "" {
+		format, err = secretfile.ParseFormat(formatName)
+	} else if path == "-" {
+		err = fmt.Errorf("--format is required when reading from stdin")
+	} else {
+		format, err = secretfile.DetectFormat(path)
+	}
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	// The confirmation prompt needs stdin, so it cannot also carry the file
+	if !dryRun && !yes && (path == "-" || !isTerminal(env.Stdin)) {
+		return usageErrorf("refusing to import without confirmation, pass --yes or --dry-run")
+	}
+
+	data, err := readValue(env, path)
+	if err != nil {
+		return err
+	}
+	pairs, err := secretfile.Decode(data, format)
+	zero(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(pairs) == 0 {
+		return fmt.Errorf("no secrets found in %s", path)
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	secrets, err := gcp.ListAllSecrets(ctx, s.store, "", s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretList(s.projectID, len(secrets), audit.ResultSuccess, "")
+
+	plan, err := bulk.PlanImport(ctx, s.store, s.audit, secrets, pairs, bulk.ImportOptions{
+		Prefix:     prefix,
+		Separator:  env.Config.FolderSeparator,
+		Labels:     labels,
+		Replicas:   spec.Replicas,
+		KMSKeyName: spec.KMSKeyName,
+	})
+	if err != nil {
+		return err
+	}
+	defer plan.Zero()
+
+	if err := writePlan(env.Stdout, plan); err != nil {
+		return err
+	}
+	if dryRun || !plan.HasChanges() {
+		return nil
+	}
+	if !yes && !confirm(env, fmt.Sprintf("Apply these changes to project %s?", s.projectID)) {
+		return fmt.Errorf("aborted")
+	}
+
+	result, err := plan.Apply(ctx, s.store, s.audit)
+	fmt.Fprintf(env.Stderr, "Created %d secrets, added %d versions\n", result.Created, result.Updated)
+	return err
+}
+
+// planSymbols mark each action in the plan output
+var planSymbols = map[bulk.Action]string{
+	bulk.ActionCreate:     "+",
+	bulk.ActionAddVersion: "~",
+	bulk.ActionUnchanged:  "=",
+	bulk.ActionSkip:       "!",
+}
+
+func writePlan(w io.Writer, plan *bulk.Plan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, item := range plan.Items {
+		fmt.Fprintf(tw, "%s %s\t%s\n", planSymbols[item.Action], item.Secret, item.Describe())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d unchanged, %d skipped\n",
+		plan.Count(bulk.ActionCreate), plan.Count(bulk.ActionAddVersion),
+		plan.Count(bulk.ActionUnchanged), plan.Count(bulk.ActionSkip))
+	return err
+}
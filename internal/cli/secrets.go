@@ -0,0 +1,398 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// secretJSON is the JSON representation of a secret in `list -o json`
+type secretJSON struct {
+	Name        string            `json:"name"`
+	Location    string            `json:"location,omitempty"`
+	FullName    string            `json:"full_name"`
+	CreateTime  string            `json:"create_time"`
+	Replication string            `json:"replication"`
+	KMSKeys     map[string]string `json:"kms_keys,omitempty"`
+	ExpireTime  string            `json:"expire_time,omitempty"`
+	Rotation    *rotationJSON     `json:"rotation,omitempty"`
+	Topics      []string          `json:"topics,omitempty"`
+	Aliases     map[string]int64  `json:"aliases,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// rotationJSON is the rotation schedule of a secret in `list -o json`
+type rotationJSON struct {
+	NextRotationTime string `json:"next_rotation_time"`
+	RotationPeriod   string `json:"rotation_period,omitempty"`
+}
+
+func runList(ctx context.Context, env Env, args []string) error {
+	var projectID, output, prefix, filter string
+	fs := newFlagSet(env, "list", &projectID)
+	fs.StringVar(&output, "output", "table", "Output format: table, json or names")
+	fs.StringVar(&output, "o", "table", "Output format (shorthand)")
+	fs.StringVar(&prefix, "prefix", "", "Only list secrets whose name starts with PATH")
+	fs.StringVar(&filter, "filter", "", "Secret Manager filter applied by the server, e.g. labels.team=payments")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return usageErrorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	if output != "table" && output != "json" && output != "names" {
+		return usageErrorf("invalid output format %q", output)
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	secrets, err := gcp.ListAllSecrets(ctx, s.store, filter, s.locations...)
+	if err != nil {
+		s.audit.LogSecretList(s.projectID, 0, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretList(s.projectID, len(secrets), audit.ResultSuccess, "")
+
+	var filtered []gcp.Secret
+	for _, secret := range secrets {
+		if strings.HasPrefix(secret.Name, prefix) {
+			filtered = append(filtered, secret)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].ID() < filtered[j].ID()
+	})
+
+	switch output {
+	case "json":
+		return writeSecretsJSON(env.Stdout, filtered)
+	case "names":
+		for _, secret := range filtered {
+			fmt.Fprintln(env.Stdout, secret.ID())
+		}
+		return nil
+	default:
+		return writeSecretsTable(env.Stdout, filtered)
+	}
+}
+
+func writeSecretsJSON(w io.Writer, secrets []gcp.Secret) error {
+	out := make([]secretJSON, 0, len(secrets))
+	for _, secret := range secrets {
+		out = append(out, secretJSON{
+			Name:        secret.Name,
+			Location:    secret.Location,
+			FullName:    secret.FullName,
+			CreateTime:  secret.CreateTime,
+			Replication: secret.Replication,
+			KMSKeys:     secret.KMSKeys(),
+			ExpireTime:  formatExpireTimeJSON(secret.ExpireTime),
+			Rotation:    toRotationJSON(secret.Rotation),
+			Topics:      secret.Topics,
+			Aliases:     secret.Aliases,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeSecretsTable(w io.Writer, secrets []gcp.Secret) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tLOCATION\tCREATED\tEXPIRES\tREPLICATION\tLABELS")
+	now := time.Now()
+	for _, secret := range secrets {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", secret.Name, formatLocation(secret.Location), secret.CreateTime, formatExpireTime(secret.ExpireTime, now), secret.Replication, formatLabels(secret.Labels))
+	}
+	return tw.Flush()
+}
+
+// formatExpireTime renders an expiration time with the time left, - when
+// the secret never expires
+func formatExpireTime(expire, now time.Time) string {
+	if expire.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", expire.UTC().Format("2006-01-02 15:04:05"), gcp.FormatRemaining(expire, now))
+}
+
+// toRotationJSON describes a rotation schedule, nil when there is none
+func toRotationJSON(r gcp.Rotation) *rotationJSON {
+	if r.IsZero() {
+		return nil
+	}
+	out := &rotationJSON{NextRotationTime: r.NextRotationTime.UTC().Format(time.RFC3339)}
+	if r.Period != 0 {
+		out.RotationPeriod = gcp.FormatPeriod(r.Period)
+	}
+	return out
+}
+
+// formatExpireTimeJSON renders an expiration time as RFC 3339, empty when
+// the secret never expires
+func formatExpireTimeJSON(expire time.Time) string {
+	if expire.IsZero() {
+		return ""
+	}
+	return expire.UTC().Format(time.RFC3339)
+}
+
+// formatLocation renders the location of a secret, global when it has none
+func formatLocation(location string) string {
+	if location == "" {
+		return "global"
+	}
+	return location
+}
+
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	return gcp.FormatLabels(labels)
+}
+
+func runGet(ctx context.Context, env Env, args []string) error {
+	var projectID, version string
+	fs := newFlagSet(env, "get", &projectID)
+	fs.StringVar(&version, "version", "latest", "Version number, alias or latest to access")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one secret name")
+	}
+	name := rest[0]
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	value, err := s.store.AccessSecretVersion(ctx, name, version)
+	if err != nil {
+		s.audit.LogSecretAccess(s.projectID, name, version, audit.ResultFailure, err.Error())
+		return err
+	}
+	defer zero(value)
+	s.audit.LogSecretAccess(s.projectID, name, version, audit.ResultSuccess, "")
+
+	_, err = env.Stdout.Write(value)
+	return err
+}
+
+func runPut(ctx context.Context, env Env, args []string) error {
+	var projectID, fromFile, kmsKey, expires, rotationPeriod, nextRotation string
+	var labelPairs, annotationPairs, locations, topics stringList
+	fs := newFlagSet(env, "put", &projectID)
+	fs.StringVar(&fromFile, "from-file", "", "Read the value from FILE instead of stdin (- for stdin)")
+	fs.Var(&labelPairs, "label", "Label KEY=VALUE for a new secret (repeatable)")
+	fs.Var(&annotationPairs, "annotation", "Annotation KEY=VALUE for a new secret, e.g. runbook=https://... (repeatable)")
+	fs.Var(&locations, "location", "Replica region for a new secret, repeatable (default: automatic replication)")
+	fs.StringVar(&kmsKey, "kms-key", "", "Cloud KMS key encrypting a new secret (default: the key saved for its location)")
+	fs.StringVar(&expires, "expires", "", "Expiry of a new secret: a TTL such as 90d or a date such as 2030-01-31")
+	fs.Var(&topics, "topic", "Pub/Sub topic projects/P/topics/T notified of a new secret's events (repeatable)")
+	fs.StringVar(&rotationPeriod, "rotation-period", "", "Rotation period of a new secret, e.g. 30d (needs --topic)")
+	fs.StringVar(&nextRotation, "next-rotation", "", "First rotation of a new secret as a TTL or date (default: one period from now)")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one secret name")
+	}
+	name := rest[0]
+	labels, err := parseLabels(labelPairs)
+	if err != nil {
+		return err
+	}
+	annotations, err := parseAnnotations(annotationPairs)
+	if err != nil {
+		return err
+	}
+	var expireTime time.Time
+	if expires != "" {
+		if expireTime, err = gcp.ParseExpiry(expires, time.Now()); err != nil {
+			return usageErrorf("%v", err)
+		}
+	}
+	rotation, err := parseRotation(rotationPeriod, nextRotation, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := gcp.ValidateRotation(rotation, topics); err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	value, err := readValue(env, fromFile)
+	if err != nil {
+		return err
+	}
+	defer zero(value)
+	if len(value) == 0 {
+		return fmt.Errorf("refusing to add an empty version")
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// Create the secret on first use
+	if _, err := s.store.GetSecret(ctx, name); err != nil {
+		if !gcp.IsNotFound(err) {
+			return err
+		}
+		spec := gcp.Secret{Labels: labels, Annotations: annotations, Replicas: gcp.ReplicasIn(locations...), ExpireTime: expireTime,
+			Rotation: rotation, Topics: topics}
+		spec.Name, spec.Location = gcp.ParseSecretID(name)
+		if spec.Location != "" && len(locations) > 0 {
+			return usageErrorf("--location cannot be used with regional secret %s", name)
+		}
+		if err := encryptSpec(env, &spec, kmsKey); err != nil {
+			return err
+		}
+		if err := s.store.CreateSecret(ctx, spec); err != nil {
+			s.audit.LogSecretCreate(s.projectID, name, audit.ResultFailure, err.Error())
+			return err
+		}
+		s.audit.LogSecretCreate(s.projectID, name, audit.ResultSuccess, "")
+		fmt.Fprintf(env.Stderr, "Created secret %s\n", name)
+	}
+
+	version, err := s.store.AddSecretVersion(ctx, name, value)
+	if err != nil {
+		s.audit.LogVersionAdd(s.projectID, name, "", audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogVersionAdd(s.projectID, name, version.Name, audit.ResultSuccess, "")
+	fmt.Fprintf(env.Stderr, "Added version %s to %s\n", version.Name, name)
+	return nil
+}
+
+// parseRotation builds a rotation schedule from the put flags. Without a
+// next rotation time, the first rotation happens one period from now.
+func parseRotation(period, next string, now time.Time) (gcp.Rotation, error) {
+	var rotation gcp.Rotation
+	var err error
+	if period != "" {
+		if rotation.Period, err = gcp.ParseTTL(period); err != nil {
+			return rotation, usageErrorf("--rotation-period: %v", err)
+		}
+		rotation.NextRotationTime = now.Add(rotation.Period).UTC()
+	}
+	if next != "" {
+		if rotation.NextRotationTime, err = gcp.ParseExpiry(next, now); err != nil {
+			return rotation, usageErrorf("--next-rotation: %v", err)
+		}
+	}
+	return rotation, nil
+}
+
+// encryptSpec sets the customer-managed keys of a new secret: kmsKey, or the
+// keys saved in config for its locations. Each replica of a user-managed
+// secret gets its own key, otherwise the key encrypts the regional or
+// automatic secret itself. A single kmsKey only fits a single location.
+func encryptSpec(env Env, spec *gcp.Secret, kmsKey string) error {
+	if len(spec.Replicas) > 1 && kmsKey != "" {
+		return usageErrorf("--kms-key needs a single --location, save a key per location in secret_kms_keys instead")
+	}
+	keyFor := func(location string) (string, error) {
+		key := kmsKey
+		if key == "" && location != "" {
+			key = env.Config.SecretKMSKey(location)
+		}
+		if key == "" {
+			return "", nil
+		}
+		if err := gcp.ValidateKMSKeyName(key, location); err != nil {
+			return "", usageErrorf("%v", err)
+		}
+		return key, nil
+	}
+
+	if len(spec.Replicas) == 0 {
+		key, err := keyFor(spec.Location)
+		spec.KMSKeyName = key
+		return err
+	}
+	for i := range spec.Replicas {
+		key, err := keyFor(spec.Replicas[i].Location)
+		if err != nil {
+			return err
+		}
+		spec.Replicas[i].KMSKeyName = key
+	}
+	return nil
+}
+
+// readValue reads a secret value from a file or, by default, stdin
+func readValue(env Env, path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		if isTerminal(env.Stdin) {
+			fmt.Fprintln(env.Stderr, "Reading value from stdin, finish with Ctrl+D")
+		}
+		return io.ReadAll(env.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func runDelete(ctx context.Context, env Env, args []string) error {
+	var projectID string
+	var yes bool
+	fs := newFlagSet(env, "delete", &projectID)
+	fs.BoolVar(&yes, "yes", false, "Do not ask for confirmation")
+	fs.BoolVar(&yes, "y", false, "Do not ask for confirmation (shorthand)")
+	rest, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return usageErrorf("expected exactly one secret name")
+	}
+	name := rest[0]
+
+	if !yes {
+		if !isTerminal(env.Stdin) {
+			return usageErrorf("refusing to delete without confirmation, pass --yes")
+		}
+		if !confirm(env, fmt.Sprintf("Delete secret %s and all its versions?", name)) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	s, err := openSession(ctx, env, projectID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := s.store.DeleteSecret(ctx, name); err != nil {
+		s.audit.LogSecretDelete(s.projectID, name, audit.ResultFailure, err.Error())
+		return err
+	}
+	s.audit.LogSecretDelete(s.projectID, name, audit.ResultSuccess, "")
+	fmt.Fprintf(env.Stderr, "Deleted secret %s\n", name)
+	return nil
+}
@@ -34,16 +34,53 @@ type SessionConfig struct {
 	LockOnTimeout     bool `yaml:"lock_on_timeout"`
 }
 
+// EnvConfig holds the rules for deriving environment variable names from secret names
+type EnvConfig struct {
+	Naming string `yaml:"naming"`           // relative, leaf or full
+	Prefix string `yaml:"prefix,omitempty"` // Prepended to every derived name
+}
+
+// APIConfig holds the limits for Secret Manager calls made by the TUI
+type APIConfig struct {
+	TimeoutSeconds int `yaml:"timeout_seconds"` // Per operation, 0 = no timeout
+}
+
+// ExpiryConfig holds the settings for secrets with an expiration time
+type ExpiryConfig struct {
+	WarnDays int `yaml:"warn_days"` // Secrets expiring within this many days are flagged
+}
+
+// Credentials selects how GCP is called; empty fields use Application
+// Default Credentials as the caller
+type Credentials struct {
+	File                      string `yaml:"file,omitempty"`                        // Service account key or external account JSON
+	ImpersonateServiceAccount string `yaml:"impersonate_service_account,omitempty"` // Service account to act as
+}
+
+// CredentialsConfig holds the default credentials and per-project overrides
+type CredentialsConfig struct {
+	Credentials `yaml:",inline"`
+	Projects    map[string]Credentials `yaml:"projects,omitempty"` // Replace the defaults for these projects
+}
+
 // Config holds the application configuration
 type Config struct {
-	ProjectID        string          `yaml:"project_id"`
-	FolderSeparator  string          `yaml:"folder_separator"`
-	Templates        []Template      `yaml:"templates"`
-	RecentProjects   []string        `yaml:"recent_projects"`
-	SecretLocations  []string        `yaml:"secret_locations,omitempty"`
-	Clipboard        ClipboardConfig `yaml:"clipboard"`
-	Audit            AuditConfig     `yaml:"audit"`
-	Session          SessionConfig   `yaml:"session"`
+	ProjectID       string            `yaml:"project_id"`
+	FolderSeparator string            `yaml:"folder_separator"`
+	Templates       []Template        `yaml:"templates"`
+	RecentProjects  []string          `yaml:"recent_projects"`
+	SecretLocations []string          `yaml:"secret_locations,omitempty"`
+	SecretKMSKeys   map[string]string `yaml:"secret_kms_keys,omitempty"` // Cloud KMS key per secret location
+	Clipboard       ClipboardConfig   `yaml:"clipboard"`
+	Audit           AuditConfig       `yaml:"audit"`
+	Session         SessionConfig     `yaml:"session"`
+	Env             EnvConfig         `yaml:"env"`
+	API             APIConfig         `yaml:"api"`
+	Expiry          ExpiryConfig      `yaml:"expiry"`
+	Credentials     CredentialsConfig `yaml:"credentials,omitempty"`
+
+	// readOnly disables Save, used by demo mode to keep the real config untouched
+	readOnly bool
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -65,10 +102,19 @@ func DefaultConfig() *Config {
 			InactivityTimeout: 15, // 15 minutes default
 			LockOnTimeout:     true,
 		},
+		Env: EnvConfig{
+			Naming: "relative",
+		},
+		API: APIConfig{
+			TimeoutSeconds: 30,
+		},
+		Expiry: ExpiryConfig{
+			WarnDays: 7,
+		},
 		Templates: []Template{
 			{
 				Title: "Bash Export",
-				Code:  `export {{.SecretName}}=$(gcloud secrets versions access latest --secret="{{.FullSecretName}}" --project="{{.ProjectID}}")`,
+				Code:  `export {{.SecretName}}=$(gcloud secrets versions access {{.Version}} --secret="{{.FullSecretName}}"{{if .Location}} --location="{{.Location}}"{{end}} --project="{{.ProjectID}}")`,
 			},
 			{
 				Title: "Helmfile secretRef",
@@ -106,7 +152,7 @@ spec:
 
 // Access the secret
 result, err := client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
-    Name: "projects/{{.ProjectID}}/secrets/{{.FullSecretName}}/versions/latest",
+    Name: "{{.ResourceName}}/versions/{{.Version}}",
 })`,
 			},
 		},
@@ -187,8 +233,17 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// DisablePersistence makes Save a no-op for this config
+func (c *Config) DisablePersistence() {
+	c.readOnly = true
+}
+
 // Save writes the config to disk with secure permissions
 func (c *Config) Save() error {
+	if c.readOnly {
+		return nil
+	}
+
 	configPath, err := GetConfigPath()
 	if err != nil {
 		return err
@@ -255,7 +310,7 @@ func (c *Config) AddSecretLocation(location string) {
 	c.SecretLocations = append(c.SecretLocations, location)
 }
 
-// RemoveSecretLocation removes a location from the saved list
+// RemoveSecretLocation removes a location and its KMS key from the saved list
 func (c *Config) RemoveSecretLocation(location string) {
 	filtered := make([]string, 0, len(c.SecretLocations))
 	for _, l := range c.SecretLocations {
@@ -264,4 +319,32 @@ func (c *Config) RemoveSecretLocation(location string) {
 		}
 	}
 	c.SecretLocations = filtered
+	delete(c.SecretKMSKeys, location)
+}
+
+// SecretKMSKey returns the KMS key saved for a location, empty for none
+func (c *Config) SecretKMSKey(location string) string {
+	return c.SecretKMSKeys[location]
+}
+
+// SetSecretKMSKey saves the KMS key used for new replicas in location;
+// an empty key goes back to Google-managed encryption
+func (c *Config) SetSecretKMSKey(location, key string) {
+	if key == "" {
+		delete(c.SecretKMSKeys, location)
+		return
+	}
+	if c.SecretKMSKeys == nil {
+		c.SecretKMSKeys = make(map[string]string)
+	}
+	c.SecretKMSKeys[location] = key
+}
+
+// CredentialsFor returns the credentials configured for a project, falling
+// back to the defaults when it has no entry of its own
+func (c *Config) CredentialsFor(projectID string) Credentials {
+	if creds, ok := c.Credentials.Projects[projectID]; ok {
+		return creds
+	}
+	return c.Credentials.Credentials
 }
@@ -0,0 +1,148 @@
+// Package filter parses the list filter query language.
+//
+// A query is a space-separated list of terms that must all match:
+//
+//	db                  name contains "db" (case-insensitive)
+//	name~db             full secret name contains "db"
+//	name=app/db/pass    full secret name equals the value
+//	label:team=payments label team equals payments
+//	label:team          label team is set
+//	env:prod            shorthand for label:env=prod
+//	-label:deprecated   any term prefixed with - is negated
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is what a term matches against
+type Kind int
+
+const (
+	KindText      Kind = iota // Displayed item name contains Value
+	KindNameLike              // Secret name contains Value
+	KindNameEqual             // Secret name equals Value
+	KindLabel                 // Label Key equals Value
+	KindLabelSet              // Label Key is present
+)
+
+// Term is one condition of a query
+type Term struct {
+	Kind   Kind
+	Key    string
+	Value  string
+	Negate bool
+}
+
+// Query is a parsed filter; the zero value matches everything
+type Query struct {
+	Terms []Term
+}
+
+// Parse parses a filter query
+func Parse(s string) (Query, error) {
+	var q Query
+	for _, field := range strings.Fields(s) {
+		term, err := parseTerm(field)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	return q, nil
+}
+
+func parseTerm(field string) (Term, error) {
+	var t Term
+	if strings.HasPrefix(field, "-") && len(field) > 1 {
+		t.Negate = true
+		field = field[1:]
+	}
+
+	switch {
+	case strings.HasPrefix(field, "name~"):
+		t.Kind, t.Value = KindNameLike, strings.TrimPrefix(field, "name~")
+	case strings.HasPrefix(field, "name="):
+		t.Kind, t.Value = KindNameEqual, strings.TrimPrefix(field, "name=")
+	case strings.HasPrefix(field, "label:"):
+		rest := strings.TrimPrefix(field, "label:")
+		key, value, hasValue := strings.Cut(rest, "=")
+		if key == "" {
+			return Term{}, fmt.Errorf("missing label key in %q", field)
+		}
+		t.Kind, t.Key, t.Value = KindLabel, key, value
+		if !hasValue {
+			t.Kind = KindLabelSet
+		}
+	default:
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			t.Kind, t.Value = KindText, field
+			break
+		}
+		if key == "" {
+			return Term{}, fmt.Errorf("missing label key in %q", field)
+		}
+		t.Kind, t.Key, t.Value = KindLabel, key, value
+	}
+
+	if (t.Kind == KindNameLike || t.Kind == KindNameEqual) && t.Value == "" {
+		return Term{}, fmt.Errorf("missing name in %q", field)
+	}
+	return t, nil
+}
+
+// IsEmpty reports whether the query has no terms
+func (q Query) IsEmpty() bool {
+	return len(q.Terms) == 0
+}
+
+// HasSecretTerms reports whether the query has terms on secret names or labels,
+// which folders match through the secrets below them
+func (q Query) HasSecretTerms() bool {
+	for _, t := range q.Terms {
+		if t.Kind != KindText {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchText checks the plain text terms against a displayed item name
+func (q Query) MatchText(itemName string) bool {
+	lower := strings.ToLower(itemName)
+	for _, t := range q.Terms {
+		if t.Kind != KindText {
+			continue
+		}
+		if strings.Contains(lower, strings.ToLower(t.Value)) == t.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchSecret checks the name and label terms against a secret
+func (q Query) MatchSecret(name string, labels map[string]string) bool {
+	for _, t := range q.Terms {
+		var ok bool
+		switch t.Kind {
+		case KindText:
+			continue
+		case KindNameLike:
+			ok = strings.Contains(strings.ToLower(name), strings.ToLower(t.Value))
+		case KindNameEqual:
+			ok = name == t.Value
+		case KindLabel:
+			v, set := labels[t.Key]
+			ok = set && v == t.Value
+		case KindLabelSet:
+			_, ok = labels[t.Key]
+		}
+		if ok == t.Negate {
+			return false
+		}
+	}
+	return true
+}
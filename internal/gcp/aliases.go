@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// maxAliasLength is the longest version alias Secret Manager accepts
+const maxAliasLength = 63
+
+// ValidateAlias checks a version alias the way Secret Manager does: 1 to 63
+// letters, digits, - and _, starting with a letter. "latest" is reserved.
+func ValidateAlias(alias string) error {
+	if alias == "" || len(alias) > maxAliasLength {
+		return fmt.Errorf("invalid alias %q, must be 1 to %d characters", alias, maxAliasLength)
+	}
+	if alias == "latest" {
+		return fmt.Errorf("alias %q is reserved", alias)
+	}
+	for i, r := range alias {
+		letter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if i == 0 && !letter {
+			return fmt.Errorf("invalid alias %q, must start with a letter", alias)
+		}
+		if !letter && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return fmt.Errorf("invalid alias %q, use letters, digits, - and _", alias)
+		}
+	}
+	return nil
+}
+
+// ValidateAliases checks every alias of a secret and the versions they point at
+func ValidateAliases(aliases map[string]int64) error {
+	for alias, version := range aliases {
+		if err := ValidateAlias(alias); err != nil {
+			return err
+		}
+		if version < 1 {
+			return fmt.Errorf("alias %s points at invalid version %d", alias, version)
+		}
+	}
+	return nil
+}
+
+// AliasesOf returns the aliases pointing at a version, sorted
+func AliasesOf(aliases map[string]int64, version string) []string {
+	var names []string
+	for alias, number := range aliases {
+		if strconv.FormatInt(number, 10) == version {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AttachAliases sets the Aliases of each version from the aliases of their
+// secret, which is where Secret Manager stores them
+func AttachAliases(versions []SecretVersion, aliases map[string]int64) {
+	for i := range versions {
+		versions[i].Aliases = AliasesOf(aliases, versions[i].Name)
+	}
+}
+
+// WithAlias returns a copy of aliases where alias points at version, or
+// without alias when version is empty
+func WithAlias(aliases map[string]int64, alias, version string) (map[string]int64, error) {
+	out := make(map[string]int64, len(aliases)+1)
+	for k, v := range aliases {
+		out[k] = v
+	}
+	if version == "" {
+		delete(out, alias)
+		return out, nil
+	}
+	number, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q for alias %s", version, alias)
+	}
+	out[alias] = number
+	return out, ValidateAliases(out)
+}
+
+// AliasVersion returns the version an alias points at, empty when the
+// secret has no such alias
+func (s Secret) AliasVersion(alias string) string {
+	number, ok := s.Aliases[alias]
+	if !ok {
+		return ""
+	}
+	return strconv.FormatInt(number, 10)
+}
@@ -0,0 +1,57 @@
+package gcp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Annotation limits enforced by Secret Manager
+const (
+	MaxAnnotationKeyLength = 63
+	MaxAnnotationsSize     = 16 * 1024 // Total bytes of all keys and values
+)
+
+// ValidateAnnotationKey checks an annotation key: 1-63 characters, starting
+// and ending with a letter or digit, with letters, digits, -, _ and . between
+func ValidateAnnotationKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("annotation key must not be empty")
+	}
+	if len(key) > MaxAnnotationKeyLength {
+		return fmt.Errorf("annotation key %q is longer than %d characters", key, MaxAnnotationKeyLength)
+	}
+	for i, r := range key {
+		alnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if alnum {
+			continue
+		}
+		if i == 0 || i == len(key)-1 {
+			return fmt.Errorf("annotation key %q must start and end with a letter or digit", key)
+		}
+		if r != '-' && r != '_' && r != '.' {
+			return fmt.Errorf("annotation key %q contains %q; only letters, digits, -, _ and . are allowed", key, r)
+		}
+	}
+	return nil
+}
+
+// ValidateAnnotations checks annotation keys and the total size of an
+// annotation set. Values are free-form.
+func ValidateAnnotations(annotations map[string]string) error {
+	keys := make([]string, 0, len(annotations))
+	size := 0
+	for key, value := range annotations {
+		keys = append(keys, key)
+		size += len(key) + len(value)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ValidateAnnotationKey(key); err != nil {
+			return err
+		}
+	}
+	if size >= MaxAnnotationsSize {
+		return fmt.Errorf("annotations take %d bytes, they must be less than %d", size, MaxAnnotationsSize)
+	}
+	return nil
+}
@@ -9,20 +9,54 @@ import (
 	"os"
 	"os/user"
 	"strings"
+	"sync"
+	"time"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Secret represents a GCP secret
 type Secret struct {
 	Name        string
+	Location    string // Region of a regional secret, empty for global secrets
 	FullName    string
 	CreateTime  string
 	Labels      map[string]string
+	Annotations map[string]string // Free-form metadata such as an owner or a runbook URL
 	Replication string
+	Replicas    []Replica        // User-managed replicas, empty for automatic replication
+	KMSKeyName  string           // Customer-managed key of automatic replication or a regional secret
+	ExpireTime  time.Time        // When Secret Manager deletes the secret, zero if it never expires
+	Rotation    Rotation         // Rotation schedule, zero when the secret is not rotated
+	Topics      []string         // Pub/Sub topics notified of changes and rotations
+	Aliases     map[string]int64 // Version number by alias, e.g. current: 3
+	Etag        string           // Version of the metadata, sent back on updates to detect conflicts
+}
+
+// Replica is a location a user-managed secret is replicated to
+type Replica struct {
+	Location   string
+	KMSKeyName string // Cloud KMS key encrypting the replica, empty for a Google-managed key
+}
+
+// ReplicasIn returns user-managed replicas for the given locations, ignoring
+// empty ones. No locations means automatic replication.
+func ReplicasIn(locations ...string) []Replica {
+	var replicas []Replica
+	for _, location := range locations {
+		if location != "" {
+			replicas = append(replicas, Replica{Location: location})
+		}
+	}
+	return replicas
 }
 
 // SecretVersion represents a version of a secret
@@ -30,6 +64,7 @@ type SecretVersion struct {
 	Name       string
 	State      string
 	CreateTime string
+	Aliases    []string // Aliases of the secret pointing at this version, sorted
 }
 
 // Client wraps the GCP Secret Manager client
@@ -37,30 +72,98 @@ type Client struct {
 	client    *secretmanager.Client
 	projectID string
 	userEmail string
+	principal string                // Identity calls are authorized as, differs from userEmail when impersonating
+	opts      []option.ClientOption // Authentication of every API client
+
+	mu       sync.Mutex
+	regional map[string]*secretmanager.Client // Regional endpoint clients, opened on first use
 }
 
-// NewClient creates a new GCP Secret Manager client
-func NewClient(ctx context.Context, projectID string) (*Client, error) {
-	client, err := secretmanager.NewClient(ctx)
+// NewClient creates a new GCP Secret Manager client authenticated with creds
+func NewClient(ctx context.Context, projectID string, creds Credentials) (*Client, error) {
+	opts, base, err := clientOptions(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := secretmanager.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create secretmanager client: %w", err)
 	}
 
 	// Try to get the authenticated user email
-	userEmail := getAuthenticatedUser(ctx)
+	userEmail := getAuthenticatedUser(base)
+	principal := userEmail
+	if creds.ImpersonateServiceAccount != "" {
+		principal = creds.ImpersonateServiceAccount
+	}
 
 	return &Client{
 		client:    client,
 		projectID: projectID,
 		userEmail: userEmail,
+		principal: principal,
+		opts:      opts,
 	}, nil
 }
 
-// Close closes the client connection
+// Close closes the client connection and any regional ones
 func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for location, client := range c.regional {
+		_ = client.Close()
+		delete(c.regional, location)
+	}
 	return c.client.Close()
 }
 
+// clientFor returns the client serving secrets in location: the global one
+// for an empty location, otherwise the client of the regional endpoint
+func (c *Client) clientFor(location string) (*secretmanager.Client, error) {
+	if location == "" {
+		return c.client, nil
+	}
+	if err := ValidateLocation(location); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.regional[location]; ok {
+		return client, nil
+	}
+	// The client is kept for later calls, so it is not bound to any call's context
+	opts := append([]option.ClientOption{option.WithEndpoint(regionalEndpoint(location))}, c.opts...)
+	client, err := secretmanager.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secretmanager client for %s: %w", location, err)
+	}
+	if c.regional == nil {
+		c.regional = make(map[string]*secretmanager.Client)
+	}
+	c.regional[location] = client
+	return client, nil
+}
+
+// parent returns the resource name secrets of location live under
+func (c *Client) parent(location string) string {
+	if location == "" {
+		return fmt.Sprintf("projects/%s", c.projectID)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s", c.projectID, location)
+}
+
+// resolve returns the client and resource name for a secret ID
+func (c *Client) resolve(secretID string) (*secretmanager.Client, string, error) {
+	name, location := ParseSecretID(secretID)
+	client, err := c.clientFor(location)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, fmt.Sprintf("%s/secrets/%s", c.parent(location), name), nil
+}
+
 // ProjectID returns the current project ID
 func (c *Client) ProjectID() string {
 	return c.projectID
@@ -71,12 +174,17 @@ func (c *Client) UserEmail() string {
 	return c.userEmail
 }
 
+// Principal returns the identity calls are authorized as: the impersonated
+// service account, or the authenticated user
+func (c *Client) Principal() string {
+	return c.principal
+}
+
 // getAuthenticatedUser attempts to get the email of the authenticated GCP user
-func getAuthenticatedUser(ctx context.Context) string {
-	// Try to get credentials from Application Default Credentials
-	creds, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
-	if err != nil {
-		return getLocalUser()
+func getAuthenticatedUser(creds *google.Credentials) string {
+	// Service account keys carry their email
+	if email := credentialsEmail(creds); email != "" {
+		return email
 	}
 
 	// Try to get token and use tokeninfo endpoint
@@ -131,85 +239,151 @@ func getLocalUser() string {
 
 // ListSecrets lists all secrets in the project
 func (c *Client) ListSecrets(ctx context.Context) ([]Secret, error) {
-	parent := fmt.Sprintf("projects/%s", c.projectID)
+	return ListAllSecrets(ctx, c, "")
+}
 
-	req := &secretmanagerpb.ListSecretsRequest{
-		Parent: parent,
+// ListSecretsPage lists one page of secrets matching req.Filter
+func (c *Client) ListSecretsPage(ctx context.Context, req ListRequest) (*SecretPage, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
 	}
 
-	var secrets []Secret
-	it := c.client.ListSecrets(ctx, req)
-	for {
-		resp, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("failed to list secrets: %w", err)
-		}
-
-		// Extract just the secret name from the full path
-		parts := strings.Split(resp.Name, "/")
-		name := parts[len(parts)-1]
-
-		replication := "automatic"
-		if resp.Replication != nil {
-			if resp.Replication.GetUserManaged() != nil {
-				replication = "user-managed"
-			}
-		}
+	client, err := c.clientFor(req.Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	it := client.ListSecrets(ctx, &secretmanagerpb.ListSecretsRequest{
+		Parent: c.parent(req.Location),
+		Filter: req.Filter,
+	})
+	var resp []*secretmanagerpb.Secret
+	next, err := iterator.NewPager(it, pageSize, req.PageToken).NextPage(&resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
 
-		secrets = append(secrets, Secret{
-			Name:        name,
-			FullName:    resp.Name,
-			CreateTime:  resp.CreateTime.AsTime().Format("2006-01-02 15:04:05"),
-			Labels:      resp.Labels,
-			Replication: replication,
-		})
+	page := &SecretPage{NextPageToken: next}
+	for _, secret := range resp {
+		page.Secrets = append(page.Secrets, fromSecret(secret))
 	}
 
-	return secrets, nil
+	return page, nil
 }
 
 // GetSecret retrieves a specific secret
 func (c *Client) GetSecret(ctx context.Context, secretName string) (*Secret, error) {
-	name := fmt.Sprintf("projects/%s/secrets/%s", c.projectID, secretName)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
 
 	req := &secretmanagerpb.GetSecretRequest{
 		Name: name,
 	}
 
-	resp, err := c.client.GetSecret(ctx, req)
+	resp, err := client.GetSecret(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secret: %w", err)
 	}
 
-	replication := "automatic"
-	if resp.Replication != nil {
-		if resp.Replication.GetUserManaged() != nil {
-			replication = "user-managed"
-		}
+	secret := fromSecret(resp)
+	return &secret, nil
+}
+
+// fromSecret converts a Secret Manager secret, global or regional
+func fromSecret(secret *secretmanagerpb.Secret) Secret {
+	// Extract just the secret name from the full path
+	parts := strings.Split(secret.Name, "/")
+	location := locationOf(secret.Name)
+
+	// Regional secrets live in their location and have no replication policy
+	replication, replicas := "regional", []Replica(nil)
+	kmsKeyName := secret.GetCustomerManagedEncryption().GetKmsKeyName()
+	if location == "" {
+		replication, replicas = fromReplication(secret.Replication)
+		kmsKeyName = secret.Replication.GetAutomatic().GetCustomerManagedEncryption().GetKmsKeyName()
 	}
 
-	return &Secret{
-		Name:        secretName,
-		FullName:    resp.Name,
-		CreateTime:  resp.CreateTime.AsTime().Format("2006-01-02 15:04:05"),
-		Labels:      resp.Labels,
+	return Secret{
+		Name:        parts[len(parts)-1],
+		Location:    location,
+		FullName:    secret.Name,
+		CreateTime:  secret.CreateTime.AsTime().Format("2006-01-02 15:04:05"),
+		Labels:      secret.Labels,
+		Annotations: secret.Annotations,
 		Replication: replication,
-	}, nil
+		Replicas:    replicas,
+		KMSKeyName:  kmsKeyName,
+		ExpireTime:  fromExpireTime(secret.GetExpireTime()),
+		Rotation:    fromRotation(secret.Rotation),
+		Topics:      fromTopics(secret.Topics),
+		Aliases:     secret.VersionAliases,
+		Etag:        secret.Etag,
+	}
+}
+
+// fromExpireTime converts an expiration timestamp, nil when the secret never expires
+func fromExpireTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+// fromReplication describes a replication policy and lists its user-managed replicas
+func fromReplication(r *secretmanagerpb.Replication) (string, []Replica) {
+	userManaged := r.GetUserManaged()
+	if userManaged == nil {
+		return "automatic", nil
+	}
+	replicas := make([]Replica, 0, len(userManaged.Replicas))
+	for _, replica := range userManaged.Replicas {
+		replicas = append(replicas, Replica{
+			Location:   replica.Location,
+			KMSKeyName: replica.GetCustomerManagedEncryption().GetKmsKeyName(),
+		})
+	}
+	return "user-managed", replicas
 }
 
-// ListSecretVersions lists all versions of a secret
+// toReplication builds the replication policy for a new secret, encrypted
+// with kmsKeyName when replication is automatic
+func toReplication(replicas []Replica, kmsKeyName string) *secretmanagerpb.Replication {
+	if len(replicas) == 0 {
+		return &secretmanagerpb.Replication{
+			Replication: &secretmanagerpb.Replication_Automatic_{
+				Automatic: &secretmanagerpb.Replication_Automatic{
+					CustomerManagedEncryption: toEncryption(kmsKeyName),
+				},
+			},
+		}
+	}
+	userManaged := &secretmanagerpb.Replication_UserManaged{}
+	for _, replica := range replicas {
+		userManaged.Replicas = append(userManaged.Replicas, &secretmanagerpb.Replication_UserManaged_Replica{
+			Location:                  replica.Location,
+			CustomerManagedEncryption: toEncryption(replica.KMSKeyName),
+		})
+	}
+	return &secretmanagerpb.Replication{
+		Replication: &secretmanagerpb.Replication_UserManaged_{UserManaged: userManaged},
+	}
+}
+
+// ListSecretVersions lists all versions of a secret with their aliases
 func (c *Client) ListSecretVersions(ctx context.Context, secretName string) ([]SecretVersion, error) {
-	parent := fmt.Sprintf("projects/%s/secrets/%s", c.projectID, secretName)
+	client, parent, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
 
 	req := &secretmanagerpb.ListSecretVersionsRequest{
 		Parent: parent,
 	}
 
 	var versions []SecretVersion
-	it := c.client.ListSecretVersions(ctx, req)
+	it := client.ListSecretVersions(ctx, req)
 	for {
 		resp, err := it.Next()
 		if err == iterator.Done {
@@ -229,18 +403,29 @@ func (c *Client) ListSecretVersions(ctx context.Context, secretName string) ([]S
 		})
 	}
 
+	// Aliases are stored on the secret rather than on its versions
+	secret, err := client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: parent})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+	AttachAliases(versions, secret.VersionAliases)
+
 	return versions, nil
 }
 
-// AccessSecretVersion retrieves the payload of a secret version
+// AccessSecretVersion retrieves the payload of a secret version, given as a
+// version number, an alias or latest
 func (c *Client) AccessSecretVersion(ctx context.Context, secretName, version string) ([]byte, error) {
-	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", c.projectID, secretName, version)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access secret version: %w", err)
+	}
 
 	req := &secretmanagerpb.AccessSecretVersionRequest{
-		Name: name,
+		Name: name + "/versions/" + version,
 	}
 
-	resp, err := c.client.AccessSecretVersion(ctx, req)
+	resp, err := client.AccessSecretVersion(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to access secret version: %w", err)
 	}
@@ -248,43 +433,40 @@ func (c *Client) AccessSecretVersion(ctx context.Context, secretName, version st
 	return resp.Payload.Data, nil
 }
 
-// CreateSecret creates a new secret
-// If location is empty, uses automatic replication (global)
-// If location is provided, uses user-managed replication with that region
-func (c *Client) CreateSecret(ctx context.Context, secretName string, labels map[string]string, location string) error {
-	parent := fmt.Sprintf("projects/%s", c.projectID)
+// CreateSecret creates a new secret named secret.Name with its labels and annotations.
+// With a location the secret is regional and stored in that location only.
+// Otherwise, without replicas the secret uses automatic replication (global),
+// and user-managed replication in the replica locations with them. KMS keys
+// of the secret or its replicas select customer-managed encryption, and a
+// non-zero ExpireTime makes Secret Manager delete the secret at that time.
+// The rotation schedule notifies the secret's topics.
+func (c *Client) CreateSecret(ctx context.Context, secret Secret) error {
+	client, err := c.clientFor(secret.Location)
+	if err != nil {
+		return fmt.Errorf("failed to create secret: %w", err)
+	}
 
-	var replication *secretmanagerpb.Replication
-	if location == "" {
-		// Use automatic replication (global)
-		replication = &secretmanagerpb.Replication{
-			Replication: &secretmanagerpb.Replication_Automatic_{
-				Automatic: &secretmanagerpb.Replication_Automatic{},
-			},
-		}
+	spec := &secretmanagerpb.Secret{
+		Labels:      secret.Labels,
+		Annotations: secret.Annotations,
+		Rotation:    toRotation(secret.Rotation),
+		Topics:      toTopics(secret.Topics),
+	}
+	if secret.Location == "" {
+		spec.Replication = toReplication(secret.Replicas, secret.KMSKeyName)
 	} else {
-		// Use user-managed replication with specific location
-		replication = &secretmanagerpb.Replication{
-			Replication: &secretmanagerpb.Replication_UserManaged_{
-				UserManaged: &secretmanagerpb.Replication_UserManaged{
-					Replicas: []*secretmanagerpb.Replication_UserManaged_Replica{
-						{Location: location},
-					},
-				},
-			},
-		}
+		spec.CustomerManagedEncryption = toEncryption(secret.KMSKeyName)
+	}
+	if !secret.ExpireTime.IsZero() {
+		spec.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: timestamppb.New(secret.ExpireTime)}
 	}
-
 	req := &secretmanagerpb.CreateSecretRequest{
-		Parent:   parent,
-		SecretId: secretName,
-		Secret: &secretmanagerpb.Secret{
-			Replication: replication,
-			Labels:      labels,
-		},
+		Parent:   c.parent(secret.Location),
+		SecretId: secret.Name,
+		Secret:   spec,
 	}
 
-	_, err := c.client.CreateSecret(ctx, req)
+	_, err = client.CreateSecret(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to create secret: %w", err)
 	}
@@ -294,7 +476,10 @@ func (c *Client) CreateSecret(ctx context.Context, secretName string, labels map
 
 // AddSecretVersion adds a new version to an existing secret
 func (c *Client) AddSecretVersion(ctx context.Context, secretName string, payload []byte) (*SecretVersion, error) {
-	parent := fmt.Sprintf("projects/%s/secrets/%s", c.projectID, secretName)
+	client, parent, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add secret version: %w", err)
+	}
 
 	req := &secretmanagerpb.AddSecretVersionRequest{
 		Parent: parent,
@@ -303,7 +488,7 @@ func (c *Client) AddSecretVersion(ctx context.Context, secretName string, payloa
 		},
 	}
 
-	resp, err := c.client.AddSecretVersion(ctx, req)
+	resp, err := client.AddSecretVersion(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add secret version: %w", err)
 	}
@@ -320,13 +505,16 @@ func (c *Client) AddSecretVersion(ctx context.Context, secretName string, payloa
 
 // DeleteSecret deletes a secret and all its versions
 func (c *Client) DeleteSecret(ctx context.Context, secretName string) error {
-	name := fmt.Sprintf("projects/%s/secrets/%s", c.projectID, secretName)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
 
 	req := &secretmanagerpb.DeleteSecretRequest{
 		Name: name,
 	}
 
-	if err := c.client.DeleteSecret(ctx, req); err != nil {
+	if err := client.DeleteSecret(ctx, req); err != nil {
 		return fmt.Errorf("failed to delete secret: %w", err)
 	}
 
@@ -335,13 +523,16 @@ func (c *Client) DeleteSecret(ctx context.Context, secretName string) error {
 
 // DisableSecretVersion disables a secret version
 func (c *Client) DisableSecretVersion(ctx context.Context, secretName, version string) error {
-	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", c.projectID, secretName, version)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to disable version: %w", err)
+	}
 
 	req := &secretmanagerpb.DisableSecretVersionRequest{
-		Name: name,
+		Name: name + "/versions/" + version,
 	}
 
-	_, err := c.client.DisableSecretVersion(ctx, req)
+	_, err = client.DisableSecretVersion(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to disable version: %w", err)
 	}
@@ -351,13 +542,16 @@ func (c *Client) DisableSecretVersion(ctx context.Context, secretName, version s
 
 // EnableSecretVersion enables a secret version
 func (c *Client) EnableSecretVersion(ctx context.Context, secretName, version string) error {
-	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", c.projectID, secretName, version)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to enable version: %w", err)
+	}
 
 	req := &secretmanagerpb.EnableSecretVersionRequest{
-		Name: name,
+		Name: name + "/versions/" + version,
 	}
 
-	_, err := c.client.EnableSecretVersion(ctx, req)
+	_, err = client.EnableSecretVersion(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to enable version: %w", err)
 	}
@@ -367,13 +561,16 @@ func (c *Client) EnableSecretVersion(ctx context.Context, secretName, version st
 
 // DestroySecretVersion permanently destroys a secret version
 func (c *Client) DestroySecretVersion(ctx context.Context, secretName, version string) error {
-	name := fmt.Sprintf("projects/%s/secrets/%s/versions/%s", c.projectID, secretName, version)
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to destroy version: %w", err)
+	}
 
 	req := &secretmanagerpb.DestroySecretVersionRequest{
-		Name: name,
+		Name: name + "/versions/" + version,
 	}
 
-	_, err := c.client.DestroySecretVersion(ctx, req)
+	_, err = client.DestroySecretVersion(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to destroy version: %w", err)
 	}
@@ -381,26 +578,206 @@ func (c *Client) DestroySecretVersion(ctx context.Context, secretName, version s
 	return nil
 }
 
-// UpdateSecretLabels updates the labels of a secret
-func (c *Client) UpdateSecretLabels(ctx context.Context, secretName string, labels map[string]string) error {
-	name := fmt.Sprintf("projects/%s/secrets/%s", c.projectID, secretName)
+// UpdateSecretLabels replaces the labels of a secret. With a non-empty etag
+// the update only succeeds if the secret was not modified since that etag was
+// read; otherwise it fails with an error for which IsConflict is true.
+func (c *Client) UpdateSecretLabels(ctx context.Context, secretName string, labels map[string]string, etag string) error {
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret: &secretmanagerpb.Secret{
+			Name:   name,
+			Labels: labels,
+			Etag:   etag,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	}
+
+	_, err = client.UpdateSecret(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSecretAnnotations replaces the annotations of a secret. The etag is
+// handled like in UpdateSecretLabels.
+func (c *Client) UpdateSecretAnnotations(ctx context.Context, secretName string, annotations map[string]string, etag string) error {
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret: &secretmanagerpb.Secret{
+			Name:        name,
+			Annotations: annotations,
+			Etag:        etag,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"annotations"}},
+	}
+
+	if _, err := client.UpdateSecret(ctx, req); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
 
-	secret, err := c.client.GetSecret(ctx, &secretmanagerpb.GetSecretRequest{Name: name})
+	return nil
+}
+
+// UpdateSecretExpiration sets the time at which a secret is deleted, or
+// removes the expiration when expireTime is zero. The etag is handled like in
+// UpdateSecretLabels.
+func (c *Client) UpdateSecretExpiration(ctx context.Context, secretName string, expireTime time.Time, etag string) error {
+	client, name, err := c.resolve(secretName)
 	if err != nil {
-		return fmt.Errorf("failed to get secret: %w", err)
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	secret := &secretmanagerpb.Secret{Name: name, Etag: etag}
+	// Clearing the ttl field is how Secret Manager removes an expiration
+	mask := "ttl"
+	if !expireTime.IsZero() {
+		secret.Expiration = &secretmanagerpb.Secret_ExpireTime{ExpireTime: timestamppb.New(expireTime)}
+		mask = "expire_time"
+	}
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret:     secret,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{mask}},
 	}
 
-	secret.Labels = labels
+	if _, err := client.UpdateSecret(ctx, req); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSecretRotation replaces the rotation schedule and the topics of a
+// secret; a zero rotation removes the schedule. The etag is handled like in
+// UpdateSecretLabels.
+func (c *Client) UpdateSecretRotation(ctx context.Context, secretName string, rotation Rotation, topics []string, etag string) error {
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
 
 	req := &secretmanagerpb.UpdateSecretRequest{
-		Secret: secret,
+		Secret: &secretmanagerpb.Secret{
+			Name:     name,
+			Rotation: toRotation(rotation),
+			Topics:   toTopics(topics),
+			Etag:     etag,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"rotation", "topics"}},
+	}
+
+	if _, err := client.UpdateSecret(ctx, req); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
 	}
 
-	_, err = c.client.UpdateSecret(ctx, req)
+	return nil
+}
+
+// UpdateSecretVersionAliases replaces the version aliases of a secret. The
+// etag is handled like in UpdateSecretLabels.
+func (c *Client) UpdateSecretVersionAliases(ctx context.Context, secretName string, aliases map[string]int64, etag string) error {
+	client, name, err := c.resolve(secretName)
 	if err != nil {
 		return fmt.Errorf("failed to update secret: %w", err)
 	}
 
+	req := &secretmanagerpb.UpdateSecretRequest{
+		Secret: &secretmanagerpb.Secret{
+			Name:           name,
+			VersionAliases: aliases,
+			Etag:           etag,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"version_aliases"}},
+	}
+
+	if _, err := client.UpdateSecret(ctx, req); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return nil
+}
+
+// GetIamPolicy retrieves the IAM policy of a secret, including conditional bindings
+func (c *Client) GetIamPolicy(ctx context.Context, secretName string) (*IAMPolicy, error) {
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+
+	req := &iampb.GetIamPolicyRequest{
+		Resource: name,
+		Options:  &iampb.GetPolicyOptions{RequestedPolicyVersion: iamPolicyVersion},
+	}
+
+	policy, err := client.GetIamPolicy(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+
+	return fromPolicy(policy), nil
+}
+
+// SetIamPolicy replaces the IAM policy of a secret. The policy's etag must
+// be the one read by GetIamPolicy; when the policy changed since, the call
+// fails with an error for which IsConflict is true.
+func (c *Client) SetIamPolicy(ctx context.Context, secretName string, policy IAMPolicy) error {
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return fmt.Errorf("failed to set IAM policy: %w", err)
+	}
+
+	req := &iampb.SetIamPolicyRequest{
+		Resource: name,
+		Policy:   toPolicy(policy),
+	}
+
+	if _, err := client.SetIamPolicy(ctx, req); err != nil {
+		return fmt.Errorf("failed to set IAM policy: %w", err)
+	}
+
 	return nil
 }
 
+// TestIamPermissions returns the subset of permissions the caller holds on
+// a secret, or on the project when secretName is empty
+func (c *Client) TestIamPermissions(ctx context.Context, secretName string, permissions []string) ([]string, error) {
+	if secretName == "" {
+		// Secret Manager cannot test project permissions, Resource Manager can
+		service, err := cloudresourcemanager.NewService(ctx, c.opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to test permissions: %w", err)
+		}
+		req := &cloudresourcemanager.TestIamPermissionsRequest{Permissions: permissions}
+		resp, err := service.Projects.TestIamPermissions(c.projectID, req).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to test permissions: %w", err)
+		}
+		return resp.Permissions, nil
+	}
+
+	client, name, err := c.resolve(secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test permissions: %w", err)
+	}
+
+	req := &iampb.TestIamPermissionsRequest{
+		Resource:    name,
+		Permissions: permissions,
+	}
+
+	resp, err := client.TestIamPermissions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to test permissions: %w", err)
+	}
+
+	return resp.Permissions, nil
+}
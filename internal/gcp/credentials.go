@@ -0,0 +1,76 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Credentials selects how a Client authenticates. The zero value uses
+// Application Default Credentials.
+type Credentials struct {
+	File                      string // Service account key or external account JSON, empty for ADC
+	ImpersonateServiceAccount string // Service account email to act as, empty to act as the caller
+}
+
+// findCredentials loads the credentials of a JSON file, or Application
+// Default Credentials when file is empty
+func findCredentials(ctx context.Context, file string) (*google.Credentials, error) {
+	if file == "" {
+		creds, err := google.FindDefaultCredentials(ctx, cloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default credentials: %w", err)
+		}
+		return creds, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, data, cloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials file %s: %w", file, err)
+	}
+	return creds, nil
+}
+
+// clientOptions authenticates API clients with creds. It also returns the
+// base credentials, those of the caller when impersonating.
+func clientOptions(ctx context.Context, creds Credentials) ([]option.ClientOption, *google.Credentials, error) {
+	base, err := findCredentials(ctx, creds.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	if creds.ImpersonateServiceAccount == "" {
+		return []option.ClientOption{option.WithCredentials(base)}, base, nil
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: creds.ImpersonateServiceAccount,
+		Scopes:          []string{cloudPlatformScope},
+	}, option.WithCredentials(base))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to impersonate %s: %w", creds.ImpersonateServiceAccount, err)
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}, base, nil
+}
+
+// credentialsEmail returns the client_email of service account credentials,
+// empty for user and external account credentials
+func credentialsEmail(creds *google.Credentials) string {
+	var key struct {
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal(creds.JSON, &key); err != nil {
+		return ""
+	}
+	return key.ClientEmail
+}
@@ -0,0 +1,47 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IsNotFound reports whether err is a NotFound error from the secret store
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// IsConflict reports whether err means the secret changed since its etag was read
+func IsConflict(err error) bool {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.Aborted:
+		return true
+	case codes.FailedPrecondition:
+		return strings.Contains(strings.ToLower(st.Message()), "etag")
+	}
+	return false
+}
+
+// IsTimeout reports whether err means the call ran out of time
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded
+}
+
+// IsTransient reports whether err is worth retrying: the service was
+// unavailable or overloaded, or the call ran out of time
+func IsTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return IsTimeout(err)
+}
+
+// IsAlreadyExists reports whether err is an AlreadyExists error from the secret store
+func IsAlreadyExists(err error) bool {
+	return status.Code(err) == codes.AlreadyExists
+}
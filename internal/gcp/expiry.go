@@ -0,0 +1,78 @@
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTTL parses a duration such as 90d, 36h or 1d12h. On top of the units
+// of time.ParseDuration it accepts d for days.
+func ParseTTL(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var ttl time.Duration
+	if days, rest, ok := strings.Cut(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		ttl = time.Duration(n) * 24 * time.Hour
+		value = rest
+		if value == "" {
+			return ttl, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 90d, 36h or 1d12h", value)
+	}
+	return ttl + d, nil
+}
+
+// ParseExpiry parses when a secret should expire, either as a TTL from now
+// (see ParseTTL) or as a date (2006-01-02) or RFC 3339 time. The result must
+// lie in the future.
+func ParseExpiry(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	var expire time.Time
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		expire = t
+	} else if t, err := time.Parse("2006-01-02", value); err == nil {
+		expire = t
+	} else {
+		ttl, err := ParseTTL(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid expiry %q, use a TTL such as 90d or a date such as 2030-01-31", value)
+		}
+		expire = now.Add(ttl)
+	}
+	if !expire.After(now) {
+		return time.Time{}, fmt.Errorf("expiry %s is not in the future", expire.UTC().Format(time.RFC3339))
+	}
+	return expire.UTC(), nil
+}
+
+// FormatRemaining renders the time left before an expiry, rounded up to
+// the largest unit such as 12d, 5h or 40m, and "expired" once it has passed
+func FormatRemaining(expire, now time.Time) string {
+	left := expire.Sub(now)
+	ceil := func(unit time.Duration) int {
+		return int((left + unit - 1) / unit)
+	}
+	switch {
+	case left <= 0:
+		return "expired"
+	case left > 24*time.Hour:
+		return fmt.Sprintf("%dd", ceil(24*time.Hour))
+	case left > time.Hour:
+		return fmt.Sprintf("%dh", ceil(time.Hour))
+	}
+	return fmt.Sprintf("%dm", ceil(time.Minute))
+}
+
+// ExpiresWithin reports whether the secret has an expiration time before
+// now plus window
+func (s Secret) ExpiresWithin(window time.Duration, now time.Time) bool {
+	return !s.ExpireTime.IsZero() && s.ExpireTime.Before(now.Add(window))
+}
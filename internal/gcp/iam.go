@@ -0,0 +1,218 @@
+package gcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/iam/apiv1/iampb"
+	"google.golang.org/genproto/googleapis/type/expr"
+)
+
+// SecretAccessorRole is the role allowing to access the payload of a secret
+const SecretAccessorRole = "roles/secretmanager.secretAccessor"
+
+// iamPolicyVersion is requested and written so that conditional bindings
+// are returned and preserved
+const iamPolicyVersion = 3
+
+// IAMPolicy is the IAM policy of a secret
+type IAMPolicy struct {
+	Bindings []IAMBinding
+	Etag     []byte // Sent back on SetIamPolicy to detect concurrent changes
+}
+
+// IAMBinding grants a role to members, optionally under a condition
+type IAMBinding struct {
+	Role      string
+	Members   []string      // e.g. user:alice@example.com, serviceAccount:app@p.iam.gserviceaccount.com
+	Condition *IAMCondition // Nil for an unconditional grant
+}
+
+// IAMCondition is the CEL condition of a binding
+type IAMCondition struct {
+	Title       string
+	Description string
+	Expression  string
+}
+
+// IAMChange is a member added to or removed from a role
+type IAMChange struct {
+	Role    string
+	Member  string
+	Removed bool
+}
+
+// String renders the change as a diff line, e.g. "+ roles/x user:a@b.c"
+func (c IAMChange) String() string {
+	sign := "+"
+	if c.Removed {
+		sign = "-"
+	}
+	return fmt.Sprintf("%s %s %s", sign, c.Role, c.Member)
+}
+
+// memberTypes are the member prefixes accepted by ValidateMember
+var memberTypes = []string{"user:", "serviceAccount:", "group:", "domain:", "principal:", "principalSet:"}
+
+// ParseMember turns an email into an IAM member, guessing serviceAccount:
+// for *.gserviceaccount.com addresses and user: otherwise. Members that
+// already have a type prefix are returned as is.
+func ParseMember(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, ":") {
+		if strings.HasSuffix(value, ".gserviceaccount.com") {
+			value = "serviceAccount:" + value
+		} else {
+			value = "user:" + value
+		}
+	}
+	if err := ValidateMember(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// ValidateMember checks that member has a known type and an identity
+func ValidateMember(member string) error {
+	for _, prefix := range memberTypes {
+		if identity, ok := strings.CutPrefix(member, prefix); ok {
+			if identity == "" {
+				return fmt.Errorf("member %q has no identity", member)
+			}
+			if (prefix == "user:" || prefix == "serviceAccount:" || prefix == "group:") && !strings.Contains(identity, "@") {
+				return fmt.Errorf("member %q is not an email address", member)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid member %q, expected e.g. user:EMAIL or serviceAccount:EMAIL", member)
+}
+
+// HasMember reports whether member is granted role without a condition
+func (p IAMPolicy) HasMember(role, member string) bool {
+	for _, binding := range p.Bindings {
+		if binding.Role == role && binding.Condition == nil {
+			for _, m := range binding.Members {
+				if m == member {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// WithMember returns a copy of the policy granting role to member without
+// a condition. Conditional bindings are left untouched.
+func (p IAMPolicy) WithMember(role, member string) IAMPolicy {
+	out := p.Clone()
+	if out.HasMember(role, member) {
+		return out
+	}
+	for i, binding := range out.Bindings {
+		if binding.Role == role && binding.Condition == nil {
+			out.Bindings[i].Members = append(binding.Members, member)
+			return out
+		}
+	}
+	out.Bindings = append(out.Bindings, IAMBinding{Role: role, Members: []string{member}})
+	return out
+}
+
+// WithoutMember returns a copy of the policy no longer granting role to
+// member without a condition, dropping the binding once it is empty
+func (p IAMPolicy) WithoutMember(role, member string) IAMPolicy {
+	out := p.Clone()
+	bindings := out.Bindings[:0]
+	for _, binding := range out.Bindings {
+		if binding.Role == role && binding.Condition == nil {
+			var members []string
+			for _, m := range binding.Members {
+				if m != member {
+					members = append(members, m)
+				}
+			}
+			if len(members) == 0 {
+				continue
+			}
+			binding.Members = members
+		}
+		bindings = append(bindings, binding)
+	}
+	out.Bindings = bindings
+	return out
+}
+
+// DiffIAM lists the unconditional grants added and removed between two
+// policies, sorted by role and member
+func DiffIAM(old, new IAMPolicy) []IAMChange {
+	var changes []IAMChange
+	for _, grant := range grants(new) {
+		if !old.HasMember(grant.Role, grant.Member) {
+			changes = append(changes, grant)
+		}
+	}
+	for _, grant := range grants(old) {
+		if !new.HasMember(grant.Role, grant.Member) {
+			grant.Removed = true
+			changes = append(changes, grant)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Role != changes[j].Role {
+			return changes[i].Role < changes[j].Role
+		}
+		return changes[i].Member < changes[j].Member
+	})
+	return changes
+}
+
+// grants lists the unconditional role/member pairs of a policy
+func grants(p IAMPolicy) []IAMChange {
+	var out []IAMChange
+	for _, binding := range p.Bindings {
+		if binding.Condition == nil {
+			for _, member := range binding.Members {
+				out = append(out, IAMChange{Role: binding.Role, Member: member})
+			}
+		}
+	}
+	return out
+}
+
+// Clone returns a deep copy of the policy
+func (p IAMPolicy) Clone() IAMPolicy {
+	out := IAMPolicy{Etag: p.Etag, Bindings: make([]IAMBinding, 0, len(p.Bindings))}
+	for _, binding := range p.Bindings {
+		binding.Members = append([]string(nil), binding.Members...)
+		out.Bindings = append(out.Bindings, binding)
+	}
+	return out
+}
+
+// fromPolicy converts an IAM policy
+func fromPolicy(policy *iampb.Policy) *IAMPolicy {
+	out := &IAMPolicy{Etag: policy.Etag}
+	for _, binding := range policy.Bindings {
+		b := IAMBinding{Role: binding.Role, Members: append([]string(nil), binding.Members...)}
+		if c := binding.Condition; c != nil {
+			b.Condition = &IAMCondition{Title: c.Title, Description: c.Description, Expression: c.Expression}
+		}
+		out.Bindings = append(out.Bindings, b)
+	}
+	return out
+}
+
+// toPolicy builds an IAM policy, as version 3 to keep conditional bindings
+func toPolicy(policy IAMPolicy) *iampb.Policy {
+	out := &iampb.Policy{Version: iamPolicyVersion, Etag: policy.Etag}
+	for _, binding := range policy.Bindings {
+		b := &iampb.Binding{Role: binding.Role, Members: binding.Members}
+		if c := binding.Condition; c != nil {
+			b.Condition = &expr.Expr{Title: c.Title, Description: c.Description, Expression: c.Expression}
+		}
+		out.Bindings = append(out.Bindings, b)
+	}
+	return out
+}
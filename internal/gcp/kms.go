@@ -0,0 +1,61 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+)
+
+// ValidateKMSKeyName checks that key is the resource name of a Cloud KMS
+// key, projects/*/locations/*/keyRings/*/cryptoKeys/*, in location. Secret
+// Manager only accepts keys in the location of the data they encrypt, which
+// is global for automatic replication.
+func ValidateKMSKeyName(key, location string) error {
+	parts := strings.Split(key, "/")
+	if len(parts) != 8 || parts[0] != "projects" || parts[2] != "locations" ||
+		parts[4] != "keyRings" || parts[6] != "cryptoKeys" {
+		return fmt.Errorf("invalid KMS key %q, expected projects/P/locations/L/keyRings/R/cryptoKeys/K", key)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return fmt.Errorf("invalid KMS key %q, expected projects/P/locations/L/keyRings/R/cryptoKeys/K", key)
+		}
+	}
+	if location == "" {
+		location = "global"
+	}
+	if parts[3] != location {
+		return fmt.Errorf("KMS key %s is in %s, it must be in %s", key, parts[3], location)
+	}
+	return nil
+}
+
+// KMSKeys returns the customer-managed keys of the secret by the location
+// they protect: each replica, the region of a regional secret or global for
+// automatic replication. It is empty with Google-managed keys.
+func (s Secret) KMSKeys() map[string]string {
+	keys := make(map[string]string)
+	if s.KMSKeyName != "" {
+		location := s.Location
+		if location == "" {
+			location = "global"
+		}
+		keys[location] = s.KMSKeyName
+	}
+	for _, replica := range s.Replicas {
+		if replica.KMSKeyName != "" {
+			keys[replica.Location] = replica.KMSKeyName
+		}
+	}
+	return keys
+}
+
+// toEncryption selects customer-managed encryption with kmsKeyName, nil for
+// a Google-managed key
+func toEncryption(kmsKeyName string) *secretmanagerpb.CustomerManagedEncryption {
+	if kmsKeyName == "" {
+		return nil
+	}
+	return &secretmanagerpb.CustomerManagedEncryption{KmsKeyName: kmsKeyName}
+}
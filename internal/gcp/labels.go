@@ -0,0 +1,88 @@
+package gcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Label limits enforced by Secret Manager
+const (
+	MaxLabels      = 64
+	MaxLabelLength = 63
+)
+
+// ValidateLabelKey checks a label key: 1-63 characters, starting with a
+// lowercase letter, containing only lowercase letters, digits, _ and -
+func ValidateLabelKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("label key must not be empty")
+	}
+	if utf8.RuneCountInString(key) > MaxLabelLength {
+		return fmt.Errorf("label key %q is longer than %d characters", key, MaxLabelLength)
+	}
+	first, _ := utf8.DecodeRuneInString(key)
+	if !unicode.IsLower(first) {
+		return fmt.Errorf("label key %q must start with a lowercase letter", key)
+	}
+	if r, ok := invalidLabelRune(key); ok {
+		return fmt.Errorf("label key %q contains %q; only lowercase letters, digits, _ and - are allowed", key, r)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks a label value: at most 63 characters of
+// lowercase letters, digits, _ and -. Empty values are allowed.
+func ValidateLabelValue(key, value string) error {
+	if utf8.RuneCountInString(value) > MaxLabelLength {
+		return fmt.Errorf("value of label %q is longer than %d characters", key, MaxLabelLength)
+	}
+	if r, ok := invalidLabelRune(value); ok {
+		return fmt.Errorf("value of label %q contains %q; only lowercase letters, digits, _ and - are allowed", key, r)
+	}
+	return nil
+}
+
+// ValidateLabels checks a label set against the Secret Manager label syntax
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxLabels {
+		return fmt.Errorf("too many labels: %d (at most %d)", len(labels), MaxLabels)
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := ValidateLabelKey(key); err != nil {
+			return err
+		}
+		if err := ValidateLabelValue(key, labels[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatLabels renders labels, or annotations, as sorted key=value pairs
+// separated by commas
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func invalidLabelRune(s string) (rune, bool) {
+	for _, r := range s {
+		if unicode.IsLower(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+			continue
+		}
+		return r, true
+	}
+	return 0, false
+}
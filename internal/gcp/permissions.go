@@ -0,0 +1,63 @@
+package gcp
+
+// Secret Manager permissions checked before offering an action
+const (
+	PermissionListSecrets    = "secretmanager.secrets.list"
+	PermissionCreateSecret   = "secretmanager.secrets.create"
+	PermissionGetSecret      = "secretmanager.secrets.get"
+	PermissionUpdateSecret   = "secretmanager.secrets.update"
+	PermissionDeleteSecret   = "secretmanager.secrets.delete"
+	PermissionListVersions   = "secretmanager.versions.list"
+	PermissionAccessVersion  = "secretmanager.versions.access"
+	PermissionAddVersion     = "secretmanager.versions.add"
+	PermissionEnableVersion  = "secretmanager.versions.enable"
+	PermissionDisableVersion = "secretmanager.versions.disable"
+	PermissionDestroyVersion = "secretmanager.versions.destroy"
+	PermissionGetIamPolicy   = "secretmanager.secrets.getIamPolicy"
+	PermissionSetIamPolicy   = "secretmanager.secrets.setIamPolicy"
+)
+
+// SecretPermissions are the permissions tested on a secret
+var SecretPermissions = []string{
+	PermissionGetSecret,
+	PermissionUpdateSecret,
+	PermissionDeleteSecret,
+	PermissionListVersions,
+	PermissionAccessVersion,
+	PermissionAddVersion,
+	PermissionEnableVersion,
+	PermissionDisableVersion,
+	PermissionDestroyVersion,
+	PermissionGetIamPolicy,
+	PermissionSetIamPolicy,
+}
+
+// ProjectPermissions are the permissions tested on a project: listing and
+// creating secrets, and the secret permissions every secret inherits
+var ProjectPermissions = append([]string{PermissionListSecrets, PermissionCreateSecret}, SecretPermissions...)
+
+// Permissions records which of the tested permissions the caller holds.
+// A nil Permissions is unknown, e.g. because testing failed, and allows
+// everything so that actions are not hidden behind a failed check.
+type Permissions map[string]bool
+
+// NewPermissions records the granted subset of the tested permissions
+func NewPermissions(tested, granted []string) Permissions {
+	p := make(Permissions, len(tested))
+	for _, permission := range tested {
+		p[permission] = false
+	}
+	for _, permission := range granted {
+		p[permission] = true
+	}
+	return p
+}
+
+// Allows reports whether permission is granted or unknown
+func (p Permissions) Allows(permission string) bool {
+	if p == nil {
+		return true
+	}
+	granted, tested := p[permission]
+	return granted || !tested
+}
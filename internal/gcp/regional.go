@@ -0,0 +1,60 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecretID returns the ID a SecretStore addresses a secret by: the plain name
+// for global secrets and name@location for regional ones. Neither secret
+// names nor locations can contain @, so IDs are unambiguous.
+func SecretID(name, location string) string {
+	if location == "" {
+		return name
+	}
+	return name + "@" + location
+}
+
+// ParseSecretID splits a secret ID into its name and location, which is
+// empty for global secrets
+func ParseSecretID(id string) (name, location string) {
+	name, location, _ = strings.Cut(id, "@")
+	return name, location
+}
+
+// ID returns the SecretID of the secret
+func (s Secret) ID() string {
+	return SecretID(s.Name, s.Location)
+}
+
+// ValidateLocation checks that location looks like a Secret Manager
+// location such as europe-west1
+func ValidateLocation(location string) error {
+	if location == "" {
+		return fmt.Errorf("location is empty")
+	}
+	for _, r := range location {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return fmt.Errorf("invalid location %q", location)
+		}
+	}
+	return nil
+}
+
+// regionalEndpoint returns the Secret Manager endpoint serving the
+// regional secrets of a location
+func regionalEndpoint(location string) string {
+	return fmt.Sprintf("secretmanager.%s.rep.googleapis.com:443", location)
+}
+
+// locationOf extracts the location from a secret resource name such as
+// projects/p/locations/l/secrets/s, empty for global secrets
+func locationOf(resourceName string) string {
+	parts := strings.Split(resourceName, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package gcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Rotation limits enforced by Secret Manager
+const (
+	MinRotationPeriod = time.Hour
+	MaxTopics         = 10
+)
+
+// Rotation is the rotation schedule of a secret. Secret Manager publishes a
+// SECRET_ROTATE message to the secret's topics at NextRotationTime, then
+// moves it forward by Period when one is set.
+type Rotation struct {
+	NextRotationTime time.Time
+	Period           time.Duration // Zero for a single rotation
+}
+
+// IsZero reports whether no rotation is scheduled
+func (r Rotation) IsZero() bool {
+	return r.NextRotationTime.IsZero() && r.Period == 0
+}
+
+// Overdue reports whether the next rotation time has passed
+func (r Rotation) Overdue(now time.Time) bool {
+	return !r.NextRotationTime.IsZero() && r.NextRotationTime.Before(now)
+}
+
+// String describes the schedule, e.g. "every 30d, next 2025-01-31 00:00:00"
+func (r Rotation) String() string {
+	if r.IsZero() {
+		return "none"
+	}
+	next := r.NextRotationTime.UTC().Format("2006-01-02 15:04:05")
+	if r.Period == 0 {
+		return "once at " + next
+	}
+	return fmt.Sprintf("every %s, next %s", FormatPeriod(r.Period), next)
+}
+
+// FormatPeriod renders a rotation period in the units ParseTTL accepts,
+// using days when the period is a whole number of them
+func FormatPeriod(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
+
+// ValidateTopic checks that topic is a Pub/Sub topic resource name
+func ValidateTopic(topic string) error {
+	parts := strings.Split(topic, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "topics" || parts[1] == "" || parts[3] == "" {
+		return fmt.Errorf("invalid topic %q, expected projects/PROJECT/topics/TOPIC", topic)
+	}
+	return nil
+}
+
+// ValidateRotation checks a rotation schedule and the topics it notifies
+// the way Secret Manager does: a period of at least an hour needs a next
+// rotation time, and any rotation needs a topic to publish to
+func ValidateRotation(r Rotation, topics []string) error {
+	if len(topics) > MaxTopics {
+		return fmt.Errorf("too many topics: %d (at most %d)", len(topics), MaxTopics)
+	}
+	for _, topic := range topics {
+		if err := ValidateTopic(topic); err != nil {
+			return err
+		}
+	}
+	if r.IsZero() {
+		return nil
+	}
+	if r.Period != 0 && r.Period < MinRotationPeriod {
+		return fmt.Errorf("rotation period %s is shorter than %s", FormatPeriod(r.Period), FormatPeriod(MinRotationPeriod))
+	}
+	if r.NextRotationTime.IsZero() {
+		return fmt.Errorf("a rotation period needs a next rotation time")
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("rotation needs at least one topic to notify")
+	}
+	return nil
+}
+
+// fromRotation converts the rotation policy of a secret
+func fromRotation(r *secretmanagerpb.Rotation) Rotation {
+	var rotation Rotation
+	if t := r.GetNextRotationTime(); t != nil {
+		rotation.NextRotationTime = t.AsTime()
+	}
+	if d := r.GetRotationPeriod(); d != nil {
+		rotation.Period = d.AsDuration()
+	}
+	return rotation
+}
+
+// toRotation builds the rotation policy of a secret, nil for none
+func toRotation(r Rotation) *secretmanagerpb.Rotation {
+	if r.IsZero() {
+		return nil
+	}
+	rotation := &secretmanagerpb.Rotation{NextRotationTime: timestamppb.New(r.NextRotationTime)}
+	if r.Period != 0 {
+		rotation.RotationPeriod = durationpb.New(r.Period)
+	}
+	return rotation
+}
+
+// fromTopics lists the names of the topics of a secret
+func fromTopics(topics []*secretmanagerpb.Topic) []string {
+	var names []string
+	for _, topic := range topics {
+		names = append(names, topic.Name)
+	}
+	return names
+}
+
+// toTopics builds the topics of a secret
+func toTopics(names []string) []*secretmanagerpb.Topic {
+	var topics []*secretmanagerpb.Topic
+	for _, name := range names {
+		topics = append(topics, &secretmanagerpb.Topic{Name: name})
+	}
+	return topics
+}
@@ -0,0 +1,102 @@
+package gcp
+
+import (
+	"context"
+	"time"
+)
+
+// SecretStore is the set of secret operations used by the UI.
+// Client implements it against GCP Secret Manager; other backends
+// only need to provide the same behaviour to be usable from the TUI.
+// Methods taking a secretName accept a SecretID, so they address
+// regional secrets as name@location.
+type SecretStore interface {
+	// ProjectID returns the project the store is bound to
+	ProjectID() string
+	// UserEmail returns the identity used to talk to the store
+	UserEmail() string
+	// Principal returns the identity calls are authorized as, which is
+	// UserEmail unless a service account is impersonated
+	Principal() string
+	// Close releases any resources held by the store
+	Close() error
+
+	ListSecrets(ctx context.Context) ([]Secret, error)
+	ListSecretsPage(ctx context.Context, req ListRequest) (*SecretPage, error)
+	GetSecret(ctx context.Context, secretName string) (*Secret, error)
+	ListSecretVersions(ctx context.Context, secretName string) ([]SecretVersion, error)
+	AccessSecretVersion(ctx context.Context, secretName, version string) ([]byte, error)
+	CreateSecret(ctx context.Context, secret Secret) error
+	AddSecretVersion(ctx context.Context, secretName string, payload []byte) (*SecretVersion, error)
+	DeleteSecret(ctx context.Context, secretName string) error
+	DisableSecretVersion(ctx context.Context, secretName, version string) error
+	EnableSecretVersion(ctx context.Context, secretName, version string) error
+	DestroySecretVersion(ctx context.Context, secretName, version string) error
+	UpdateSecretLabels(ctx context.Context, secretName string, labels map[string]string, etag string) error
+	UpdateSecretAnnotations(ctx context.Context, secretName string, annotations map[string]string, etag string) error
+	UpdateSecretExpiration(ctx context.Context, secretName string, expireTime time.Time, etag string) error
+	UpdateSecretRotation(ctx context.Context, secretName string, rotation Rotation, topics []string, etag string) error
+	UpdateSecretVersionAliases(ctx context.Context, secretName string, aliases map[string]int64, etag string) error
+	GetIamPolicy(ctx context.Context, secretName string) (*IAMPolicy, error)
+	SetIamPolicy(ctx context.Context, secretName string, policy IAMPolicy) error
+	TestIamPermissions(ctx context.Context, secretName string, permissions []string) ([]string, error)
+}
+
+// DefaultPageSize is the number of secrets fetched per page when a
+// ListRequest does not set one
+const DefaultPageSize = 250
+
+// ListRequest selects one page of a secret listing
+type ListRequest struct {
+	Filter    string // Secret Manager filter, e.g. "labels.team=payments"; empty lists everything
+	Location  string // Lists the regional secrets of this location instead of the global ones
+	PageSize  int    // Zero uses DefaultPageSize
+	PageToken string // Empty for the first page
+}
+
+// SecretPage is one page of a secret listing
+type SecretPage struct {
+	Secrets       []Secret
+	NextPageToken string // Empty on the last page
+}
+
+// ListAllSecrets fetches every page of a filtered listing of the global
+// secrets, followed by the regional secrets of each of the given locations
+func ListAllSecrets(ctx context.Context, store SecretStore, filter string, locations ...string) ([]Secret, error) {
+	var secrets []Secret
+	for _, location := range append([]string{""}, locations...) {
+		req := ListRequest{Filter: filter, Location: location}
+		for {
+			page, err := store.ListSecretsPage(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, page.Secrets...)
+			if page.NextPageToken == "" {
+				break
+			}
+			req.PageToken = page.NextPageToken
+		}
+	}
+	return secrets, nil
+}
+
+// StoreOpener opens a SecretStore for the given project.
+// The UI calls it on startup and every time the user switches project.
+type StoreOpener func(ctx context.Context, projectID string) (SecretStore, error)
+
+// Ensure Client satisfies SecretStore
+var _ SecretStore = (*Client)(nil)
+
+// OpenStoreWith returns a StoreOpener backed by GCP Secret Manager that
+// authenticates to each project with the credentials credsFor returns
+func OpenStoreWith(credsFor func(projectID string) Credentials) StoreOpener {
+	return func(ctx context.Context, projectID string) (SecretStore, error) {
+		client, err := NewClient(ctx, projectID, credsFor(projectID))
+		if err != nil {
+			// Return an untyped nil so callers can compare against nil
+			return nil, err
+		}
+		return client, nil
+	}
+}
@@ -0,0 +1,94 @@
+package memstore
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// secretFilter reports whether a secret matches a list filter
+type secretFilter func(sec *secret) bool
+
+// parseFilter supports the subset of the Secret Manager filter syntax that
+// matters for browsing: terms joined by spaces or AND, each optionally
+// prefixed with NOT or -, on the fields
+//
+//	name:text         name contains text
+//	labels.key=value  label equals value
+//	labels.key:value  label contains value
+//	labels.key:*      label is set
+//
+// Anything else fails with InvalidArgument, like an unsupported filter would.
+func parseFilter(expr string) (secretFilter, error) {
+	var terms []secretFilter
+	negate := false
+	for _, field := range strings.Fields(expr) {
+		switch field {
+		case "AND":
+			continue
+		case "NOT":
+			negate = true
+			continue
+		case "OR":
+			return nil, status.Errorf(codes.InvalidArgument, "filter %q: OR is not supported", expr)
+		}
+		if strings.HasPrefix(field, "-") {
+			negate = true
+			field = field[1:]
+		}
+		term, err := parseFilterTerm(field)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "filter %q: %v", expr, err)
+		}
+		if negate {
+			inner := term
+			term = func(sec *secret) bool { return !inner(sec) }
+			negate = false
+		}
+		terms = append(terms, term)
+	}
+
+	return func(sec *secret) bool {
+		for _, term := range terms {
+			if !term(sec) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseFilterTerm(field string) (secretFilter, error) {
+	i := strings.IndexAny(field, ":=")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid term %q", field)
+	}
+	key, op, value := field[:i], field[i], strings.Trim(field[i+1:], `"`)
+
+	switch {
+	case key == "name" && op == ':':
+		return func(sec *secret) bool { return strings.Contains(sec.name, value) }, nil
+	case strings.HasPrefix(key, "labels.") && len(key) > len("labels."):
+		label := strings.TrimPrefix(key, "labels.")
+		switch {
+		case op == '=':
+			return func(sec *secret) bool {
+				v, ok := sec.labels[label]
+				return ok && v == value
+			}, nil
+		case value == "*":
+			return func(sec *secret) bool {
+				_, ok := sec.labels[label]
+				return ok
+			}, nil
+		default:
+			return func(sec *secret) bool {
+				v, ok := sec.labels[label]
+				return ok && strings.Contains(v, value)
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported term %q", field)
+}
@@ -0,0 +1,122 @@
+package memstore
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed demo.yaml
+var demoFixture []byte
+
+// Fixture describes the initial content of an in-memory backend
+type Fixture struct {
+	DefaultProject string                    `yaml:"default_project"`
+	User           string                    `yaml:"user"`
+	Projects       map[string]ProjectFixture `yaml:"projects"`
+}
+
+// ProjectFixture holds the secrets of a single project
+type ProjectFixture struct {
+	Roles   []string        `yaml:"roles,omitempty"` // roles of the fixture user, empty = roles/secretmanager.admin
+	Secrets []SecretFixture `yaml:"secrets"`
+}
+
+// SecretFixture describes a secret and its versions
+type SecretFixture struct {
+	Name             string            `yaml:"name"`
+	CreateTime       string            `yaml:"create_time,omitempty"`
+	Labels           map[string]string `yaml:"labels,omitempty"`
+	Annotations      map[string]string `yaml:"annotations,omitempty"`
+	Region           string            `yaml:"region,omitempty"`    // regional secret location, empty = global
+	Locations        []string          `yaml:"locations,omitempty"` // empty = automatic replication
+	KMSKeys          map[string]string `yaml:"kms_keys,omitempty"`  // by replica location, region or global
+	ExpireTime       string            `yaml:"expire_time,omitempty"`
+	TTL              string            `yaml:"ttl,omitempty"`             // e.g. 5d, expiry relative to when the fixture is loaded
+	Topics           []string          `yaml:"topics,omitempty"`          // projects/P/topics/T
+	RotationPeriod   string            `yaml:"rotation_period,omitempty"` // e.g. 30d, needs next_rotation_time
+	NextRotationTime string            `yaml:"next_rotation_time,omitempty"`
+	Aliases          map[string]int64  `yaml:"aliases,omitempty"`  // version number by alias, e.g. {current: 2}
+	IAM              IAMFixture        `yaml:"iam,omitempty"`      // members by role
+	Versions         []VersionFixture  `yaml:"versions,omitempty"` // oldest first
+}
+
+// IAMFixture lists the members granted each role on a secret, e.g.
+// {roles/secretmanager.secretAccessor: [user:alice@example.com]}
+type IAMFixture map[string][]string
+
+// VersionFixture describes a single secret version
+type VersionFixture struct {
+	Value      string `yaml:"value"`
+	State      string `yaml:"state,omitempty"` // ENABLED (default), DISABLED or DESTROYED
+	CreateTime string `yaml:"create_time,omitempty"`
+}
+
+// DemoFixture returns the built-in fixture used by --demo
+func DemoFixture() (*Fixture, error) {
+	return parseFixture(demoFixture)
+}
+
+// LoadFixture reads a fixture from a YAML file
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	return parseFixture(data)
+}
+
+func parseFixture(data []byte) (*Fixture, error) {
+	var f Fixture
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture: %w", err)
+	}
+
+	// Fall back to the first project (alphabetically) when no default is set
+	if f.DefaultProject == "" {
+		for name := range f.Projects {
+			if f.DefaultProject == "" || name < f.DefaultProject {
+				f.DefaultProject = name
+			}
+		}
+	}
+
+	return &f, nil
+}
+
+// parseFixtureTime parses a fixture timestamp, falling back to def when empty
+func parseFixtureTime(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	for _, layout := range []string{time.RFC3339, timeFormat, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// policy builds the unconditional bindings of the fixture, sorted by role
+func (f IAMFixture) policy() ([]gcp.IAMBinding, error) {
+	roles := make([]string, 0, len(f))
+	for role := range f {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	var policy gcp.IAMPolicy
+	for _, role := range roles {
+		for _, member := range f[role] {
+			if err := gcp.ValidateMember(member); err != nil {
+				return nil, err
+			}
+			policy = policy.WithMember(role, member)
+		}
+	}
+	return policy.Bindings, nil
+}
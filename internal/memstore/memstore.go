@@ -0,0 +1,946 @@
+// Package memstore provides an in-memory implementation of gcp.SecretStore.
+// It is used by --demo mode and to exercise the UI without a GCP project.
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeFormat matches the format used by gcp.Client for timestamps
+const timeFormat = "2006-01-02 15:04:05"
+
+// Version states, mirroring secretmanagerpb.SecretVersion_State
+const (
+	StateEnabled   = "ENABLED"
+	StateDisabled  = "DISABLED"
+	StateDestroyed = "DESTROYED"
+)
+
+// Backend holds the secrets of every project known to the in-memory store.
+// All stores opened from the same backend share its data.
+type Backend struct {
+	mu       sync.Mutex
+	user     string
+	projects map[string]*project
+}
+
+type project struct {
+	secrets map[string]*secret // By gcp.SecretID
+	roles   []string           // Roles of the backend user, none for admin
+}
+
+type secret struct {
+	name        string
+	region      string // Location of a regional secret, empty for global ones
+	createTime  time.Time
+	labels      map[string]string
+	annotations map[string]string
+	replicas    []gcp.Replica
+	kmsKey      string    // Key of automatic replication or of a regional secret
+	expireTime  time.Time // Zero when the secret never expires
+	rotation    gcp.Rotation
+	topics      []string
+	aliases     map[string]int64
+	versions    []*version // oldest first, versions[i].number == i+1
+	revision    int        // bumped on every metadata update, exposed as the etag
+	policy      []gcp.IAMBinding
+	policyRev   int // bumped on every SetIamPolicy, exposed as the policy etag
+}
+
+type version struct {
+	number     int
+	state      string
+	payload    []byte
+	createTime time.Time
+}
+
+// New creates a backend seeded from the given fixture (which may be nil)
+func New(f *Fixture) (*Backend, error) {
+	b := &Backend{
+		user:     "demo@example.com",
+		projects: make(map[string]*project),
+	}
+	if f == nil {
+		return b, nil
+	}
+	if f.User != "" {
+		b.user = f.User
+	}
+
+	// Seeded entries get deterministic timestamps unless the fixture sets them
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	loaded := time.Now().UTC()
+	for projectID, pf := range f.Projects {
+		p := b.project(projectID)
+		for _, role := range pf.Roles {
+			if _, ok := rolePermissions[role]; !ok {
+				return nil, fmt.Errorf("project %s: unknown role %q", projectID, role)
+			}
+		}
+		p.roles = pf.Roles
+		for i, sf := range pf.Secrets {
+			if sf.Name == "" {
+				return nil, fmt.Errorf("project %s: secret %d has no name", projectID, i)
+			}
+			if sf.Region != "" && len(sf.Locations) > 0 {
+				return nil, fmt.Errorf("secret %s: regional secrets have no replica locations", sf.Name)
+			}
+			created, err := parseFixtureTime(sf.CreateTime, base.Add(time.Duration(i)*time.Hour))
+			if err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			s := &secret{
+				name:       sf.Name,
+				region:     sf.Region,
+				createTime: created,
+				labels:     copyLabels(sf.Labels),
+			}
+			if err := gcp.ValidateAnnotations(sf.Annotations); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			s.annotations = copyLabels(sf.Annotations)
+			if err := s.setEncryption(fixtureSpec(sf)); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			if s.expireTime, err = fixtureExpiry(sf, loaded); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			if err := s.setRotation(sf); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			for j, vf := range sf.Versions {
+				vCreated, err := parseFixtureTime(vf.CreateTime, created.Add(time.Duration(j)*time.Minute))
+				if err != nil {
+					return nil, fmt.Errorf("secret %s version %d: %w", sf.Name, j+1, err)
+				}
+				state := vf.State
+				if state == "" {
+					state = StateEnabled
+				}
+				v := &version{number: j + 1, state: state, createTime: vCreated}
+				switch state {
+				case StateEnabled, StateDisabled:
+					v.payload = []byte(vf.Value)
+				case StateDestroyed:
+					// Destroyed versions have no payload
+				default:
+					return nil, fmt.Errorf("secret %s version %d: invalid state %q", sf.Name, j+1, vf.State)
+				}
+				s.versions = append(s.versions, v)
+			}
+			if err := s.setAliases(sf.Aliases); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			if s.policy, err = sf.IAM.policy(); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", sf.Name, err)
+			}
+			p.secrets[s.id()] = s
+		}
+	}
+
+	return b, nil
+}
+
+// Projects returns the IDs of all projects in the backend, sorted
+func (b *Backend) Projects() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ids := make([]string, 0, len(b.projects))
+	for id := range b.projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Locations returns every location used by the backend's secrets, either as
+// replica or as region of a regional secret, sorted
+func (b *Backend) Locations() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var locations []string
+	add := func(location string) {
+		if location != "" && !seen[location] {
+			seen[location] = true
+			locations = append(locations, location)
+		}
+	}
+	for _, p := range b.projects {
+		for _, sec := range p.secrets {
+			add(sec.region)
+			for _, replica := range sec.replicas {
+				add(replica.Location)
+			}
+		}
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+// Open returns a store for the given project. Unknown projects start empty.
+// Its signature matches gcp.StoreOpener.
+func (b *Backend) Open(ctx context.Context, projectID string) (gcp.SecretStore, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	b.mu.Lock()
+	b.project(projectID)
+	b.mu.Unlock()
+	return &Store{backend: b, projectID: projectID}, nil
+}
+
+// project returns the project with the given ID, creating it if needed.
+// Callers must hold b.mu.
+func (b *Backend) project(projectID string) *project {
+	p, ok := b.projects[projectID]
+	if !ok {
+		p = &project{secrets: make(map[string]*secret)}
+		b.projects[projectID] = p
+	}
+	return p
+}
+
+// Store is a gcp.SecretStore bound to one project of a Backend
+type Store struct {
+	backend   *Backend
+	projectID string
+}
+
+// Ensure Store satisfies gcp.SecretStore
+var _ gcp.SecretStore = (*Store)(nil)
+
+// ProjectID returns the current project ID
+func (s *Store) ProjectID() string {
+	return s.projectID
+}
+
+// UserEmail returns the fixture user
+func (s *Store) UserEmail() string {
+	return s.backend.user
+}
+
+// Principal returns the fixture user, the memory backend never impersonates
+func (s *Store) Principal() string {
+	return s.backend.user
+}
+
+// Close is a no-op, the data lives as long as the backend
+func (s *Store) Close() error {
+	return nil
+}
+
+// lock locks the backend and returns the store's project. Secrets past their
+// expiration time are deleted first, as Secret Manager would have.
+func (s *Store) lock() *project {
+	s.backend.mu.Lock()
+	p := s.backend.project(s.projectID)
+	now := time.Now().UTC()
+	for id, sec := range p.secrets {
+		if !sec.expireTime.IsZero() && !sec.expireTime.After(now) {
+			for _, v := range sec.versions {
+				zero(v.payload)
+			}
+			delete(p.secrets, id)
+		}
+	}
+	return p
+}
+
+func (s *Store) unlock() {
+	s.backend.mu.Unlock()
+}
+
+// ListSecrets lists all secrets in the project, sorted by name
+func (s *Store) ListSecrets(ctx context.Context) ([]gcp.Secret, error) {
+	return gcp.ListAllSecrets(ctx, s, "")
+}
+
+// ListSecretsPage lists one page of the global or regional secrets matching
+// req.Filter, sorted by name. Page tokens are offsets into the sorted listing.
+func (s *Store) ListSecretsPage(ctx context.Context, req gcp.ListRequest) (*gcp.SecretPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	match, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	start := 0
+	if req.PageToken != "" {
+		start, err = strconv.Atoi(req.PageToken)
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("failed to list secrets: %w",
+				status.Errorf(codes.InvalidArgument, "invalid page token %q", req.PageToken))
+		}
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = gcp.DefaultPageSize
+	}
+
+	p := s.lock()
+	defer s.unlock()
+
+	names := make([]string, 0, len(p.secrets))
+	for id, sec := range p.secrets {
+		if sec.region == req.Location && match(sec) {
+			names = append(names, id)
+		}
+	}
+	sort.Strings(names)
+
+	page := &gcp.SecretPage{}
+	end := start + pageSize
+	if end < len(names) {
+		page.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(names)
+	}
+	for i := start; i < end; i++ {
+		page.Secrets = append(page.Secrets, s.toSecret(p.secrets[names[i]]))
+	}
+	return page, nil
+}
+
+// GetSecret retrieves a specific secret
+func (s *Store) GetSecret(ctx context.Context, secretName string) (*gcp.Secret, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	result := s.toSecret(sec)
+	return &result, nil
+}
+
+// ListSecretVersions lists all versions of a secret with their aliases, newest first
+func (s *Store) ListSecretVersions(ctx context.Context, secretName string) ([]gcp.SecretVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions: %w", err)
+	}
+
+	versions := make([]gcp.SecretVersion, 0, len(sec.versions))
+	for i := len(sec.versions) - 1; i >= 0; i-- {
+		versions = append(versions, toVersion(sec.versions[i]))
+	}
+	gcp.AttachAliases(versions, sec.aliases)
+	return versions, nil
+}
+
+// AccessSecretVersion returns a copy of the payload of a secret version.
+// "latest" resolves to the most recently created version, and aliases to
+// the version they point at.
+func (s *Store) AccessSecretVersion(ctx context.Context, secretName, versionName string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to access secret version: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	v, err := s.findVersion(p, secretName, versionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access secret version: %w", err)
+	}
+	if v.state != StateEnabled {
+		return nil, fmt.Errorf("failed to access secret version: %w",
+			status.Errorf(codes.FailedPrecondition, "Secret Version [%s] is in %s state", s.versionPath(secretName, v.number), v.state))
+	}
+
+	payload := make([]byte, len(v.payload))
+	copy(payload, v.payload)
+	return payload, nil
+}
+
+// CreateSecret creates a new secret without versions. With a location the
+// secret is regional; otherwise, without replicas it uses automatic replication.
+func (s *Store) CreateSecret(ctx context.Context, spec gcp.Secret) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to create secret: %w", err)
+	}
+	if spec.Name == "" {
+		return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, "secret ID is required"))
+	}
+	if spec.Location != "" {
+		if err := gcp.ValidateLocation(spec.Location); err != nil {
+			return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+		}
+	}
+	secretID := spec.ID()
+	p := s.lock()
+	defer s.unlock()
+
+	if !spec.ExpireTime.IsZero() && !spec.ExpireTime.After(time.Now().UTC()) {
+		return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, "expire_time must be in the future"))
+	}
+	if err := gcp.ValidateRotation(spec.Rotation, spec.Topics); err != nil {
+		return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+	if err := gcp.ValidateAnnotations(spec.Annotations); err != nil {
+		return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+
+	if _, exists := p.secrets[secretID]; exists {
+		return fmt.Errorf("failed to create secret: %w",
+			status.Errorf(codes.AlreadyExists, "Secret [%s] already exists", s.secretPath(secretID)))
+	}
+
+	sec := &secret{
+		name:        spec.Name,
+		region:      spec.Location,
+		createTime:  time.Now().UTC(),
+		labels:      copyLabels(spec.Labels),
+		annotations: copyLabels(spec.Annotations),
+		expireTime:  spec.ExpireTime.UTC(),
+		rotation:    spec.Rotation,
+		topics:      append([]string(nil), spec.Topics...),
+	}
+	if err := sec.setEncryption(spec); err != nil {
+		return fmt.Errorf("failed to create secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+	p.secrets[secretID] = sec
+	return nil
+}
+
+// AddSecretVersion adds a new enabled version to an existing secret
+func (s *Store) AddSecretVersion(ctx context.Context, secretName string, payload []byte) (*gcp.SecretVersion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to add secret version: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add secret version: %w", err)
+	}
+
+	data := make([]byte, len(payload))
+	copy(data, payload)
+	v := &version{
+		number:     len(sec.versions) + 1,
+		state:      StateEnabled,
+		payload:    data,
+		createTime: time.Now().UTC(),
+	}
+	sec.versions = append(sec.versions, v)
+
+	result := toVersion(v)
+	return &result, nil
+}
+
+// DeleteSecret deletes a secret and all its versions
+func (s *Store) DeleteSecret(ctx context.Context, secretName string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	for _, v := range sec.versions {
+		zero(v.payload)
+	}
+	delete(p.secrets, secretName)
+	return nil
+}
+
+// DisableSecretVersion disables a secret version
+func (s *Store) DisableSecretVersion(ctx context.Context, secretName, versionName string) error {
+	return s.setVersionState(ctx, secretName, versionName, StateDisabled, "failed to disable version")
+}
+
+// EnableSecretVersion enables a secret version
+func (s *Store) EnableSecretVersion(ctx context.Context, secretName, versionName string) error {
+	return s.setVersionState(ctx, secretName, versionName, StateEnabled, "failed to enable version")
+}
+
+// DestroySecretVersion destroys a secret version and wipes its payload
+func (s *Store) DestroySecretVersion(ctx context.Context, secretName, versionName string) error {
+	return s.setVersionState(ctx, secretName, versionName, StateDestroyed, "failed to destroy version")
+}
+
+// UpdateSecretLabels replaces the labels of a secret, failing with Aborted
+// when etag is set and the secret was modified since
+func (s *Store) UpdateSecretLabels(ctx context.Context, secretName string, labels map[string]string, etag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if etag != "" && etag != sec.etag() {
+		return fmt.Errorf("failed to update secret: %w",
+			status.Errorf(codes.Aborted, "etag %s does not match the current etag of secret [%s]", etag, s.secretPath(secretName)))
+	}
+	sec.labels = copyLabels(labels)
+	sec.revision++
+	return nil
+}
+
+// UpdateSecretAnnotations replaces the annotations of a secret, failing
+// with Aborted on an etag mismatch
+func (s *Store) UpdateSecretAnnotations(ctx context.Context, secretName string, annotations map[string]string, etag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if err := gcp.ValidateAnnotations(annotations); err != nil {
+		return fmt.Errorf("failed to update secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if etag != "" && etag != sec.etag() {
+		return fmt.Errorf("failed to update secret: %w",
+			status.Errorf(codes.Aborted, "etag %s does not match the current etag of secret [%s]", etag, s.secretPath(secretName)))
+	}
+	sec.annotations = copyLabels(annotations)
+	sec.revision++
+	return nil
+}
+
+// UpdateSecretExpiration sets or, with a zero expireTime, removes the
+// expiration of a secret, failing with Aborted on an etag mismatch
+func (s *Store) UpdateSecretExpiration(ctx context.Context, secretName string, expireTime time.Time, etag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if etag != "" && etag != sec.etag() {
+		return fmt.Errorf("failed to update secret: %w",
+			status.Errorf(codes.Aborted, "etag %s does not match the current etag of secret [%s]", etag, s.secretPath(secretName)))
+	}
+	if !expireTime.IsZero() && !expireTime.After(time.Now().UTC()) {
+		return fmt.Errorf("failed to update secret: %w", status.Error(codes.InvalidArgument, "expire_time must be in the future"))
+	}
+	sec.expireTime = expireTime.UTC()
+	sec.revision++
+	return nil
+}
+
+// UpdateSecretRotation replaces the rotation schedule and topics of a
+// secret, failing with Aborted on an etag mismatch
+func (s *Store) UpdateSecretRotation(ctx context.Context, secretName string, rotation gcp.Rotation, topics []string, etag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if err := gcp.ValidateRotation(rotation, topics); err != nil {
+		return fmt.Errorf("failed to update secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if etag != "" && etag != sec.etag() {
+		return fmt.Errorf("failed to update secret: %w",
+			status.Errorf(codes.Aborted, "etag %s does not match the current etag of secret [%s]", etag, s.secretPath(secretName)))
+	}
+	sec.rotation = rotation
+	sec.topics = append([]string(nil), topics...)
+	sec.revision++
+	return nil
+}
+
+// UpdateSecretVersionAliases replaces the version aliases of a secret,
+// failing with Aborted on an etag mismatch
+func (s *Store) UpdateSecretVersionAliases(ctx context.Context, secretName string, aliases map[string]int64, etag string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	if etag != "" && etag != sec.etag() {
+		return fmt.Errorf("failed to update secret: %w",
+			status.Errorf(codes.Aborted, "etag %s does not match the current etag of secret [%s]", etag, s.secretPath(secretName)))
+	}
+	if err := sec.setAliases(aliases); err != nil {
+		return fmt.Errorf("failed to update secret: %w", status.Error(codes.InvalidArgument, err.Error()))
+	}
+	sec.revision++
+	return nil
+}
+
+// GetIamPolicy returns the IAM policy of a secret
+func (s *Store) GetIamPolicy(ctx context.Context, secretName string) (*gcp.IAMPolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM policy: %w", err)
+	}
+	policy := gcp.IAMPolicy{Bindings: sec.policy, Etag: sec.policyEtag()}.Clone()
+	return &policy, nil
+}
+
+// SetIamPolicy replaces the IAM policy of a secret, failing with Aborted
+// when the policy has an etag and was modified since
+func (s *Store) SetIamPolicy(ctx context.Context, secretName string, policy gcp.IAMPolicy) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to set IAM policy: %w", err)
+	}
+	for _, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			if err := gcp.ValidateMember(member); err != nil {
+				return fmt.Errorf("failed to set IAM policy: %w", status.Error(codes.InvalidArgument, err.Error()))
+			}
+		}
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return fmt.Errorf("failed to set IAM policy: %w", err)
+	}
+	if len(policy.Etag) > 0 && string(policy.Etag) != string(sec.policyEtag()) {
+		return fmt.Errorf("failed to set IAM policy: %w",
+			status.Error(codes.Aborted, "There were concurrent policy changes. Please retry the whole read-modify-write with exponential backoff."))
+	}
+	sec.policy = policy.Clone().Bindings
+	sec.policyRev++
+	return nil
+}
+
+// TestIamPermissions returns the permissions the backend user holds on a
+// secret, or on the project when secretName is empty. Project roles come
+// from the fixture and are inherited by secrets, which add the roles their
+// IAM policy grants to the user. Permissions are reported, not enforced.
+func (s *Store) TestIamPermissions(ctx context.Context, secretName string, permissions []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to test permissions: %w", err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	roles := append([]string(nil), p.roles...)
+	if len(roles) == 0 {
+		roles = []string{"roles/secretmanager.admin"}
+	}
+	if secretName != "" {
+		sec, err := s.find(p, secretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to test permissions: %w", err)
+		}
+		member := "user:" + s.backend.user
+		policy := gcp.IAMPolicy{Bindings: sec.policy}
+		for role := range rolePermissions {
+			if policy.HasMember(role, member) {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	var granted []string
+	for _, permission := range permissions {
+		for _, role := range roles {
+			if rolePermissions[role][permission] {
+				granted = append(granted, permission)
+				break
+			}
+		}
+	}
+	return granted, nil
+}
+
+func (s *Store) setVersionState(ctx context.Context, secretName, versionName, state, errPrefix string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	p := s.lock()
+	defer s.unlock()
+
+	v, err := s.findVersion(p, secretName, versionName)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errPrefix, err)
+	}
+	if v.state == StateDestroyed {
+		return fmt.Errorf("%s: %w", errPrefix,
+			status.Errorf(codes.FailedPrecondition, "Secret Version [%s] is in DESTROYED state", s.versionPath(secretName, v.number)))
+	}
+
+	v.state = state
+	if state == StateDestroyed {
+		zero(v.payload)
+		v.payload = nil
+		// Secret Manager drops the aliases of destroyed versions
+		sec, _ := s.find(p, secretName)
+		for alias, number := range sec.aliases {
+			if int(number) == v.number {
+				delete(sec.aliases, alias)
+			}
+		}
+	}
+	return nil
+}
+
+// find returns the named secret. Callers must hold the backend lock.
+func (s *Store) find(p *project, secretName string) (*secret, error) {
+	sec, ok := p.secrets[secretName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions", s.secretPath(secretName))
+	}
+	return sec, nil
+}
+
+// findVersion resolves a version number, an alias or "latest". Callers must hold the backend lock.
+func (s *Store) findVersion(p *project, secretName, versionName string) (*version, error) {
+	sec, err := s.find(p, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	if versionName == "latest" {
+		if len(sec.versions) == 0 {
+			return nil, status.Errorf(codes.NotFound, "Secret [%s] not found or has no versions", s.secretPath(secretName))
+		}
+		return sec.versions[len(sec.versions)-1], nil
+	}
+
+	if number, ok := sec.aliases[versionName]; ok {
+		return sec.versions[number-1], nil
+	}
+
+	n, err := strconv.Atoi(versionName)
+	if err != nil || n < 1 || n > len(sec.versions) {
+		return nil, status.Errorf(codes.NotFound, "Secret Version [%s/versions/%s] not found", s.secretPath(secretName), versionName)
+	}
+	return sec.versions[n-1], nil
+}
+
+func (s *Store) secretPath(secretID string) string {
+	name, location := gcp.ParseSecretID(secretID)
+	if location == "" {
+		return fmt.Sprintf("projects/%s/secrets/%s", s.projectID, name)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/secrets/%s", s.projectID, location, name)
+}
+
+func (s *Store) versionPath(secretName string, number int) string {
+	return fmt.Sprintf("%s/versions/%d", s.secretPath(secretName), number)
+}
+
+func (s *Store) toSecret(sec *secret) gcp.Secret {
+	replication := "automatic"
+	switch {
+	case sec.region != "":
+		replication = "regional"
+	case len(sec.replicas) > 0:
+		replication = "user-managed"
+	}
+	return gcp.Secret{
+		Name:        sec.name,
+		Location:    sec.region,
+		FullName:    s.secretPath(sec.id()),
+		CreateTime:  sec.createTime.Format(timeFormat),
+		Labels:      copyLabels(sec.labels),
+		Annotations: copyLabels(sec.annotations),
+		Replication: replication,
+		Replicas:    append([]gcp.Replica(nil), sec.replicas...),
+		KMSKeyName:  sec.kmsKey,
+		ExpireTime:  sec.expireTime,
+		Rotation:    sec.rotation,
+		Topics:      append([]string(nil), sec.topics...),
+		Aliases:     copyAliases(sec.aliases),
+		Etag:        sec.etag(),
+	}
+}
+
+// setEncryption applies the replicas and customer-managed keys of spec,
+// rejecting keys Secret Manager would not accept. Regional secrets have no
+// replication policy.
+func (sec *secret) setEncryption(spec gcp.Secret) error {
+	if spec.Location == "" {
+		sec.replicas = append([]gcp.Replica(nil), spec.Replicas...)
+	}
+	for _, replica := range sec.replicas {
+		if replica.KMSKeyName != "" {
+			if err := gcp.ValidateKMSKeyName(replica.KMSKeyName, replica.Location); err != nil {
+				return err
+			}
+		}
+	}
+	if spec.KMSKeyName != "" {
+		if len(sec.replicas) > 0 {
+			return fmt.Errorf("user-managed replication sets a KMS key per replica")
+		}
+		if err := gcp.ValidateKMSKeyName(spec.KMSKeyName, spec.Location); err != nil {
+			return err
+		}
+	}
+	sec.kmsKey = spec.KMSKeyName
+	return nil
+}
+
+// fixtureSpec describes the replication and encryption of a fixture secret
+func fixtureSpec(sf SecretFixture) gcp.Secret {
+	spec := gcp.Secret{Location: sf.Region}
+	for _, location := range sf.Locations {
+		spec.Replicas = append(spec.Replicas, gcp.Replica{Location: location, KMSKeyName: sf.KMSKeys[location]})
+	}
+	if len(spec.Replicas) == 0 {
+		location := sf.Region
+		if location == "" {
+			location = "global"
+		}
+		spec.KMSKeyName = sf.KMSKeys[location]
+	}
+	return spec
+}
+
+// fixtureExpiry returns the expiration of a fixture secret, given either as
+// a timestamp or as a TTL counted from when the fixture is loaded
+func fixtureExpiry(sf SecretFixture, loaded time.Time) (time.Time, error) {
+	switch {
+	case sf.ExpireTime != "" && sf.TTL != "":
+		return time.Time{}, fmt.Errorf("expire_time and ttl are mutually exclusive")
+	case sf.TTL != "":
+		ttl, err := gcp.ParseTTL(sf.TTL)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return loaded.Add(ttl), nil
+	}
+	return parseFixtureTime(sf.ExpireTime, time.Time{})
+}
+
+// setRotation applies the rotation schedule and topics of a fixture secret
+func (sec *secret) setRotation(sf SecretFixture) error {
+	var rotation gcp.Rotation
+	if sf.RotationPeriod != "" {
+		period, err := gcp.ParseTTL(sf.RotationPeriod)
+		if err != nil {
+			return err
+		}
+		rotation.Period = period
+	}
+	next, err := parseFixtureTime(sf.NextRotationTime, time.Time{})
+	if err != nil {
+		return err
+	}
+	rotation.NextRotationTime = next
+	if err := gcp.ValidateRotation(rotation, sf.Topics); err != nil {
+		return err
+	}
+	sec.rotation = rotation
+	sec.topics = append([]string(nil), sf.Topics...)
+	return nil
+}
+
+// setAliases replaces the version aliases of a secret, which must point at
+// existing versions that are not destroyed
+func (sec *secret) setAliases(aliases map[string]int64) error {
+	if err := gcp.ValidateAliases(aliases); err != nil {
+		return err
+	}
+	for alias, number := range aliases {
+		if number > int64(len(sec.versions)) {
+			return fmt.Errorf("alias %s points at version %d, which does not exist", alias, number)
+		}
+		if sec.versions[number-1].state == StateDestroyed {
+			return fmt.Errorf("alias %s points at version %d, which is destroyed", alias, number)
+		}
+	}
+	sec.aliases = copyAliases(aliases)
+	return nil
+}
+
+func (sec *secret) id() string {
+	return gcp.SecretID(sec.name, sec.region)
+}
+
+func (sec *secret) etag() string {
+	return strconv.Quote(strconv.Itoa(sec.revision))
+}
+
+func (sec *secret) policyEtag() []byte {
+	return []byte("BwY" + strconv.Itoa(sec.policyRev))
+}
+
+func toVersion(v *version) gcp.SecretVersion {
+	return gcp.SecretVersion{
+		Name:       strconv.Itoa(v.number),
+		State:      v.state,
+		CreateTime: v.createTime.Format(timeFormat),
+	}
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
+
+func copyAliases(aliases map[string]int64) map[string]int64 {
+	if len(aliases) == 0 {
+		return nil
+	}
+	out := make(map[string]int64, len(aliases))
+	for k, v := range aliases {
+		out[k] = v
+	}
+	return out
+}
+
+// zero wipes a payload buffer before it is released
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
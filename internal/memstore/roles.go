@@ -0,0 +1,32 @@
+package memstore
+
+import "github.com/theburrowhub/go-secret/internal/gcp"
+
+// rolePermissions lists the Secret Manager permissions of the predefined
+// roles fixtures and secret IAM policies can grant
+var rolePermissions = map[string]map[string]bool{
+	"roles/secretmanager.admin": permissionSet(gcp.ProjectPermissions...),
+	"roles/secretmanager.viewer": permissionSet(
+		gcp.PermissionListSecrets,
+		gcp.PermissionGetSecret,
+		gcp.PermissionListVersions,
+		gcp.PermissionGetIamPolicy,
+	),
+	gcp.SecretAccessorRole:                   permissionSet(gcp.PermissionAccessVersion),
+	"roles/secretmanager.secretVersionAdder": permissionSet(gcp.PermissionAddVersion),
+	"roles/secretmanager.secretVersionManager": permissionSet(
+		gcp.PermissionListVersions,
+		gcp.PermissionAddVersion,
+		gcp.PermissionEnableVersion,
+		gcp.PermissionDisableVersion,
+		gcp.PermissionDestroyVersion,
+	),
+}
+
+func permissionSet(permissions ...string) map[string]bool {
+	set := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		set[permission] = true
+	}
+	return set
+}
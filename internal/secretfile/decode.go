@@ -0,0 +1,238 @@
+package secretfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DetectFormat guesses the format from a file name; YAML files holding a
+// Kubernetes Secret are recognized when decoded
+func DetectFormat(path string) (Format, error) {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return FormatDotenv, nil
+	case strings.HasSuffix(base, ".json"):
+		return FormatJSON, nil
+	case strings.HasSuffix(base, ".yaml") || strings.HasSuffix(base, ".yml"):
+		return FormatYAML, nil
+	default:
+		return "", fmt.Errorf("cannot detect the format of %s, use --format", path)
+	}
+}
+
+// Decode parses pairs from data in the given format.
+// Duplicate keys are an error rather than silently overriding each other.
+func Decode(data []byte, format Format) ([]Pair, error) {
+	var pairs []Pair
+	var err error
+	switch format {
+	case FormatDotenv:
+		pairs, err = decodeDotenv(data)
+	case FormatJSON:
+		pairs, err = decodeJSON(data)
+	case FormatYAML, FormatK8s:
+		pairs, err = decodeYAML(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(pairs))
+	for _, p := range pairs {
+		if p.Key == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+		if seen[p.Key] {
+			return nil, fmt.Errorf("duplicate key %s", p.Key)
+		}
+		seen[p.Key] = true
+	}
+	return sortPairs(pairs), nil
+}
+
+func decodeDotenv(data []byte) ([]Pair, error) {
+	var pairs []Pair
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		value, err := unquoteDotenv(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		pairs = append(pairs, Pair{Key: strings.TrimSpace(key), Value: []byte(value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dotenv: %w", err)
+	}
+	return pairs, nil
+}
+
+// unquoteDotenv reverses quoteDotenv. Single-quoted values are literal,
+// unquoted values end at an inline " #" comment.
+func unquoteDotenv(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		var b strings.Builder
+		escaped := false
+		for i, r := range raw[1:] {
+			if escaped {
+				switch r {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteRune(r)
+				}
+				escaped = false
+				continue
+			}
+			switch r {
+			case '\\':
+				escaped = true
+			case '"':
+				if rest := strings.TrimSpace(raw[i+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+					return "", fmt.Errorf("unexpected text after closing quote")
+				}
+				return b.String(), nil
+			default:
+				b.WriteRune(r)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return raw[1 : end+1], nil
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+func decodeJSON(data []byte) ([]Pair, error) {
+	// Numbers keep their literal text instead of going through float64
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	pairs := make([]Pair, 0, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case string:
+			pairs = append(pairs, Pair{Key: k, Value: []byte(v)})
+		case json.Number:
+			pairs = append(pairs, Pair{Key: k, Value: []byte(v.String())})
+		case nil:
+			pairs = append(pairs, Pair{Key: k})
+		case map[string]any, []any:
+			return nil, fmt.Errorf("key %s: nested values are not supported", k)
+		default:
+			pairs = append(pairs, Pair{Key: k, Value: []byte(fmt.Sprint(v))})
+		}
+	}
+	return pairs, nil
+}
+
+// decodeYAML reads a flat mapping, keeping every scalar as written: 0123
+// stays 0123 rather than becoming the octal 83, and dates stay as typed
+func decodeYAML(data []byte) ([]Pair, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	obj := doc.Content[0]
+	if obj.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse YAML: expected a mapping of keys to values")
+	}
+	if isManifest(obj) {
+		return decodeManifest(data)
+	}
+
+	pairs := make([]Pair, 0, len(obj.Content)/2)
+	for i := 0; i+1 < len(obj.Content); i += 2 {
+		k, v := obj.Content[i].Value, obj.Content[i+1]
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+		switch {
+		case v.Kind != yaml.ScalarNode:
+			return nil, fmt.Errorf("key %s: nested values are not supported", k)
+		case v.Tag == "!!null":
+			pairs = append(pairs, Pair{Key: k})
+		default:
+			pairs = append(pairs, Pair{Key: k, Value: []byte(v.Value)})
+		}
+	}
+	return pairs, nil
+}
+
+// isManifest reports whether a YAML mapping is a Kubernetes Secret
+func isManifest(obj *yaml.Node) bool {
+	kind, apiVersion := false, false
+	for i := 0; i+1 < len(obj.Content); i += 2 {
+		switch obj.Content[i].Value {
+		case "kind":
+			kind = obj.Content[i+1].Value == "Secret"
+		case "apiVersion":
+			apiVersion = obj.Content[i+1].Tag != "!!null"
+		}
+	}
+	return kind && apiVersion
+}
+
+// decodeManifest reads both data (base64) and stringData of a Kubernetes Secret
+func decodeManifest(data []byte) ([]Pair, error) {
+	var secret struct {
+		Data       map[string]string `yaml:"data"`
+		StringData map[string]string `yaml:"stringData"`
+	}
+	if err := yaml.Unmarshal(data, &secret); err != nil {
+		return nil, fmt.Errorf("failed to parse Secret manifest: %w", err)
+	}
+	pairs := make([]Pair, 0, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		value, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid base64: %w", k, err)
+		}
+		pairs = append(pairs, Pair{Key: k, Value: value})
+	}
+	for k, v := range secret.StringData {
+		pairs = append(pairs, Pair{Key: k, Value: []byte(v)})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,86 @@
+package secretfile
+
+import "testing"
+
+func TestDecodeKeepsScalarsVerbatim(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		want   map[string]string
+	}{
+		{
+			name:   "json large integer",
+			format: FormatJSON,
+			data:   `{"ID": 12345678901234567890, "RATE": 1.50, "ON": true, "EMPTY": null}`,
+			want:   map[string]string{"ID": "12345678901234567890", "RATE": "1.50", "ON": "true", "EMPTY": ""},
+		},
+		{
+			name:   "yaml octal-looking pin",
+			format: FormatYAML,
+			data:   "PIN: 0123\n",
+			want:   map[string]string{"PIN": "0123"},
+		},
+		{
+			name:   "yaml date",
+			format: FormatYAML,
+			data:   "SINCE: 2024-01-01\n",
+			want:   map[string]string{"SINCE": "2024-01-01"},
+		},
+		{
+			name:   "yaml scalars",
+			format: FormatYAML,
+			data:   "ON: yes\nHEX: 0x1F\nQUOTED: '007'\nBLOCK: |\n  line 1\n  line 2\nEMPTY:\n",
+			want: map[string]string{
+				"ON": "yes", "HEX": "0x1F", "QUOTED": "007", "BLOCK": "line 1\nline 2\n", "EMPTY": "",
+			},
+		},
+		{
+			name:   "kubernetes secret",
+			format: FormatYAML,
+			data:   "apiVersion: v1\nkind: Secret\ndata:\n  A: MDEyMw==\nstringData:\n  B: 0123\n",
+			want:   map[string]string{"A": "0123", "B": "0123"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := Decode([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			got := make(map[string]string, len(pairs))
+			for _, p := range pairs {
+				got[p.Key] = string(p.Value)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d pairs %q, want %d", len(got), got, len(tt.want))
+			}
+			for k, want := range tt.want {
+				if got[k] != want {
+					t.Errorf("%s = %q, want %q", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsNestedAndDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"json nested", FormatJSON, `{"A": {"B": "c"}}`},
+		{"yaml nested", FormatYAML, "A:\n  B: c\n"},
+		{"yaml list", FormatYAML, "A: [1, 2]\n"},
+		{"yaml duplicate", FormatYAML, "A: 1\nA: 2\n"},
+		{"yaml not a mapping", FormatYAML, "- A\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.data), tt.format); err == nil {
+				t.Fatal("Decode succeeded, want an error")
+			}
+		})
+	}
+}
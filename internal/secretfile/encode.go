@@ -0,0 +1,126 @@
+package secretfile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestOptions sets the metadata of a Kubernetes Secret manifest
+type ManifestOptions struct {
+	Name      string
+	Namespace string
+}
+
+// Encode writes pairs to w in the given format, sorted by key
+func Encode(w io.Writer, format Format, pairs []Pair, opts ManifestOptions) error {
+	pairs = sortPairs(pairs)
+	switch format {
+	case FormatDotenv:
+		return encodeDotenv(w, pairs)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stringMap(pairs))
+	case FormatYAML:
+		return encodeYAML(w, stringMap(pairs))
+	case FormatK8s:
+		return encodeManifest(w, pairs, opts)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func stringMap(pairs []Pair) map[string]string {
+	m := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		m[p.Key] = string(p.Value)
+	}
+	return m
+}
+
+func encodeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// dotenvBare matches values that need no quoting in a dotenv file
+var dotenvBare = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]+$`)
+
+func encodeDotenv(w io.Writer, pairs []Pair) error {
+	for _, p := range pairs {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", p.Key, quoteDotenv(string(p.Value))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteDotenv double-quotes a value when needed, escaping backslashes,
+// quotes, dollars and line breaks so multi-line values stay on one line
+func quoteDotenv(v string) string {
+	if dotenvBare.MatchString(v) {
+		return v
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range v {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '$':
+			b.WriteString(`\$`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// manifest is a Kubernetes Secret
+type manifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   manifestMetadata  `yaml:"metadata"`
+	Type       string            `yaml:"type"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type manifestMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+func encodeManifest(w io.Writer, pairs []Pair, opts ManifestOptions) error {
+	data := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		data[p.Key] = base64.StdEncoding.EncodeToString(p.Value)
+	}
+	name := opts.Name
+	if name == "" {
+		name = "secrets"
+	}
+	return encodeYAML(w, manifest{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   manifestMetadata{Name: name, Namespace: opts.Namespace},
+		Type:       "Opaque",
+		Data:       data,
+	})
+}
@@ -0,0 +1,90 @@
+// Package secretfile reads and writes sets of secrets as dotenv, JSON,
+// YAML and Kubernetes Secret files.
+package secretfile
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Format is a file format for a set of secrets
+type Format string
+
+const (
+	FormatDotenv Format = "dotenv"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatK8s    Format = "k8s"
+)
+
+// Formats lists the supported formats in display order
+var Formats = []Format{FormatDotenv, FormatJSON, FormatYAML, FormatK8s}
+
+// ParseFormat validates a format name
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if Format(s) == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (expected dotenv, json, yaml or k8s)", s)
+}
+
+// Extension returns the usual file extension for the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatDotenv:
+		return ".env"
+	case FormatJSON:
+		return ".json"
+	default:
+		return ".yaml"
+	}
+}
+
+// Pair is one key with its secret value
+type Pair struct {
+	Key   string
+	Value []byte
+}
+
+// sortPairs orders pairs by key so output is stable
+func sortPairs(pairs []Pair) []Pair {
+	sorted := append([]Pair(nil), pairs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// Create creates or truncates path for writing secrets.
+// The file is only readable by its owner, even if it already existed.
+func Create(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := f.Chmod(0600); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to secure %s: %w", path, err)
+	}
+	return f, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// ManifestName derives a valid Kubernetes object name from a folder path
+func ManifestName(folder, sep string) string {
+	name := strings.Trim(strings.ReplaceAll(strings.ToLower(folder), sep, "-"), "-")
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-.")
+	if len(name) > 253 {
+		name = strings.Trim(name[:253], "-.")
+	}
+	if name == "" {
+		return "secrets"
+	}
+	return name
+}
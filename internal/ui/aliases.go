@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+type aliasUpdatedMsg struct {
+	secretName string
+	alias      string
+	oldVersion string      // Version the alias pointed at, empty when it is new
+	version    string      // Version the alias points at, empty when removed
+	secret     *gcp.Secret // Secret as stored after the update or the conflict
+	err        error
+}
+
+// aliasBadges renders the aliases pointing at a version for the versions list
+func (m Model) aliasBadges(v gcp.SecretVersion) string {
+	if len(v.Aliases) == 0 {
+		return ""
+	}
+	return "  " + m.styles.StatusInfo.Render("@"+strings.Join(v.Aliases, " @"))
+}
+
+// openAlias shows the dialog assigning or removing an alias of the version
+// under the cursor
+func (m Model) openAlias() (tea.Model, tea.Cmd) {
+	if len(m.versions) == 0 {
+		return m, nil
+	}
+	version := m.versions[m.versionCursor]
+	if version.State == "DESTROYED" {
+		m.statusMsg = fmt.Sprintf("Version %s is destroyed and cannot have aliases", version.Name)
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.aliasInput = textinput.New()
+	m.aliasInput.Placeholder = "current"
+	m.aliasInput.CharLimit = 63
+	m.aliasInput.Width = 46
+	if len(version.Aliases) > 0 {
+		m.aliasInput.SetValue(version.Aliases[0])
+	}
+	m.aliasInput.Focus()
+	m.aliasTarget = version.Name
+	m.view = ViewAlias
+	return m, textinput.Blink
+}
+
+func (m Model) updateAlias(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "ctrl+d":
+		alias := strings.TrimSpace(m.aliasInput.Value())
+		if err := gcp.ValidateAlias(alias); err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		secret := *m.selectedSecret
+		version := m.aliasTarget
+		if msg.String() == "ctrl+d" {
+			version = ""
+		}
+		if secret.AliasVersion(alias) == version {
+			if version == "" {
+				m.statusMsg = fmt.Sprintf("Secret has no alias %s", alias)
+				m.statusErr = true
+				return m, nil
+			}
+			m.view = ViewDetail
+			return m, nil
+		}
+		aliases, err := gcp.WithAlias(secret.Aliases, alias, version)
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = "Updating aliases..."
+		return m, m.updateVersionAliases(secret, alias, version, aliases)
+	case "esc":
+		m.view = ViewDetail
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.aliasInput, cmd = m.aliasInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateVersionAliases(secret gcp.Secret, alias, version string, aliases map[string]int64) tea.Cmd {
+	secretName := secret.ID()
+	oldVersion := secret.AliasVersion(alias)
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		msg := aliasUpdatedMsg{secretName: secretName, alias: alias, oldVersion: oldVersion, version: version}
+		msg.err = m.client.UpdateSecretVersionAliases(ctx, secretName, aliases, secret.Etag)
+		if msg.err == nil || gcp.IsConflict(msg.err) {
+			// Pick up the new etag, or what someone else changed
+			msg.secret, _ = m.client.GetSecret(ctx, secretName)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) handleAliasUpdated(msg aliasUpdatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		m.auditLogger.LogAliasUpdate(m.config.ProjectID, msg.secretName, msg.alias, msg.oldVersion, msg.version, result, errMsg)
+	}
+
+	if m.selectedSecret != nil && m.selectedSecret.ID() == msg.secretName {
+		if msg.err == nil {
+			m.selectedSecret.Aliases, _ = gcp.WithAlias(m.selectedSecret.Aliases, msg.alias, msg.version)
+			m.selectedSecret.Etag = ""
+		}
+		if msg.secret != nil {
+			m.selectedSecret.Aliases = msg.secret.Aliases
+			m.selectedSecret.Etag = msg.secret.Etag
+		}
+		gcp.AttachAliases(m.versions, m.selectedSecret.Aliases)
+	}
+
+	if msg.err != nil {
+		if gcp.IsConflict(msg.err) && msg.secret != nil {
+			// Stay in the dialog so the edit can be applied to the reloaded secret
+			m.statusMsg = "Secret was changed by someone else, aliases were reloaded. Press Enter to apply anyway"
+		} else {
+			m.statusMsg = fmt.Sprintf("Error updating aliases: %v", msg.err)
+		}
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.view = ViewDetail
+	switch {
+	case msg.version == "":
+		m.statusMsg = fmt.Sprintf("✓ Alias %s removed", msg.alias)
+	case msg.oldVersion != "":
+		m.statusMsg = fmt.Sprintf("✓ Alias %s moved from version %s to %s", msg.alias, msg.oldVersion, msg.version)
+	default:
+		m.statusMsg = fmt.Sprintf("✓ Alias %s points at version %s", msg.alias, msg.version)
+	}
+	m.statusErr = false
+	return m, nil
+}
+
+func (m Model) viewAlias() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Alias version %s of %s", m.aliasTarget, m.selectedSecret.ID())))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Aliases: "))
+	if len(m.selectedSecret.Aliases) == 0 {
+		b.WriteString(m.styles.SubtleText().Render("none"))
+	} else {
+		var aliases []string
+		for alias := range m.selectedSecret.Aliases {
+			aliases = append(aliases, fmt.Sprintf("%s → v%s", alias, m.selectedSecret.AliasVersion(alias)))
+		}
+		sort.Strings(aliases)
+		b.WriteString(m.styles.DetailValue.Render(strings.Join(aliases, ", ")))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Alias:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.InputFocused.Width(50).Render(m.aliasInput.View()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("Enter points the alias at this version, moving it if it exists"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Ctrl+D removes the alias from the secret"))
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+// templateVersions lists the versions templates can target: latest and the
+// aliases of the selected secret
+func (m Model) templateVersions() []string {
+	versions := []string{"latest"}
+	if m.selectedSecret == nil {
+		return versions
+	}
+	var aliases []string
+	for alias := range m.selectedSecret.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return append(versions, aliases...)
+}
+
+// defaultTemplateVersion targets the first alias of the version under the
+// cursor, or latest when it has none
+func (m Model) defaultTemplateVersion() string {
+	if len(m.versions) > 0 && len(m.versions[m.versionCursor].Aliases) > 0 {
+		return m.versions[m.versionCursor].Aliases[0]
+	}
+	return "latest"
+}
+
+// nextTemplateVersion cycles the version targeted by generated templates
+func (m *Model) nextTemplateVersion() {
+	versions := m.templateVersions()
+	for i, version := range versions {
+		if version == m.templateVersion {
+			m.templateVersion = versions[(i+1)%len(versions)]
+			return
+		}
+	}
+	m.templateVersion = versions[0]
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// urlPattern matches the http(s) URLs highlighted in annotation values
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// annotationLines renders the annotations of the selected secret for the
+// detail view, one "key: value" per line, or "none"
+func (m Model) annotationLines() []string {
+	annotations := m.selectedSecret.Annotations
+	if len(annotations) == 0 {
+		return []string{m.styles.SubtleText().Render("none")}
+	}
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		// Multi-line values are shown on one line
+		value := strings.Join(strings.Fields(annotations[key]), " ")
+		lines = append(lines, m.styles.SubtleText().Render(key+": ")+m.renderLinks(value))
+	}
+	return lines
+}
+
+// renderLinks renders text with its URLs styled as links
+func (m Model) renderLinks(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		if loc[0] > last {
+			b.WriteString(m.styles.DetailValue.Render(text[last:loc[0]]))
+		}
+		b.WriteString(m.styles.DetailLink.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(text) {
+		b.WriteString(m.styles.DetailValue.Render(text[last:]))
+	}
+	return b.String()
+}
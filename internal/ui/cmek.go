@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// newKMSInput creates a KMS key field of the create dialog
+func newKMSInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "projects/P/locations/L/keyRings/R/cryptoKeys/K"
+	input.CharLimit = 300
+	input.Width = 46
+	return input
+}
+
+// createFields is the number of fields of the create dialog: name, value and
+// locations, a KMS key per selected replica location, the expiry and the
+// location of a regional secret
+func (m Model) createFields() int {
+	return 5 + len(m.createLocations())
+}
+
+// syncCreateKMS adds a KMS key field for every selected location, filled
+// with the key saved for it. Keys already typed are kept.
+func (m *Model) syncCreateKMS() {
+	inputs := make(map[string]textinput.Model)
+	for _, location := range m.createLocations() {
+		input, ok := m.createKMSInputs[location]
+		if !ok {
+			input = newKMSInput()
+			input.SetValue(m.config.SecretKMSKey(location))
+		}
+		inputs[location] = input
+	}
+	m.createKMSInputs = inputs
+}
+
+// createKMSLocation returns the location of the KMS key field with focus,
+// empty when another field has it
+func (m Model) createKMSLocation() string {
+	locations := m.createLocations()
+	if i := m.createFocus - 3; i >= 0 && i < len(locations) {
+		return locations[i]
+	}
+	return ""
+}
+
+func (m *Model) focusCreateKMS() tea.Cmd {
+	location := m.createKMSLocation()
+	input := m.createKMSInputs[location]
+	input.Focus()
+	m.createKMSInputs[location] = input
+	return textinput.Blink
+}
+
+func (m *Model) blurCreateKMS() {
+	location := m.createKMSLocation()
+	input := m.createKMSInputs[location]
+	input.Blur()
+	m.createKMSInputs[location] = input
+}
+
+func (m Model) updateCreateKMS(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	location := m.createKMSLocation()
+	input, cmd := m.createKMSInputs[location].Update(msg)
+	m.createKMSInputs[location] = input
+	return m, cmd
+}
+
+// createReplicas returns the replicas of the secret being created. A KMS key
+// entered for a location is validated and saved as its default.
+func (m *Model) createReplicas() ([]gcp.Replica, error) {
+	var replicas []gcp.Replica
+	changed := false
+	for _, location := range m.createLocations() {
+		key := strings.TrimSpace(m.createKMSInputs[location].Value())
+		if key != "" {
+			if err := gcp.ValidateKMSKeyName(key, location); err != nil {
+				return nil, err
+			}
+		}
+		if key != m.config.SecretKMSKey(location) {
+			m.config.SetSecretKMSKey(location, key)
+			changed = true
+		}
+		replicas = append(replicas, gcp.Replica{Location: location, KMSKeyName: key})
+	}
+	if changed {
+		_ = m.config.Save()
+	}
+	return replicas, nil
+}
+
+func (m Model) viewCreateKMS() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.InputLabel.Render("KMS Keys:"))
+	b.WriteString(m.styles.SubtleText().Render("  customer-managed key per location, empty for Google-managed"))
+	for i, location := range m.createLocations() {
+		b.WriteString("\n")
+		b.WriteString(m.styles.SubtleText().Render("📍 " + location))
+		b.WriteString("\n")
+		inputStyle := m.styles.Input
+		if m.createFocus == 3+i {
+			inputStyle = m.styles.InputFocused
+		}
+		b.WriteString(inputStyle.Width(50).Render(m.createKMSInputs[location].View()))
+	}
+	return b.String()
+}
+
+// encryptionLines describes how the selected secret is encrypted, one line
+// per customer-managed key
+func (m Model) encryptionLines() []string {
+	keys := m.selectedSecret.KMSKeys()
+	if len(keys) == 0 {
+		return []string{"Google-managed"}
+	}
+	locations := make([]string, 0, len(keys))
+	for location := range keys {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	lines := make([]string, 0, len(locations))
+	for _, location := range locations {
+		lines = append(lines, fmt.Sprintf("%s: %s", location, keys[location]))
+	}
+	return lines
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listLayout holds the columns shown next to secret names in the list
+type listLayout struct {
+	nameWidth     int
+	locationWidth int
+	expiryWidth   int
+	location      bool // Regional secrets are listed
+	expiry        bool // A visible secret has an expiration time
+	rotation      bool // A visible secret is overdue for rotation
+}
+
+// listColumns lays out the columns for the visible items. The location
+// column is only shown when regional secrets are listed, the expiry column
+// when one of the items expires and the rotation column when one is overdue.
+func (m Model) listColumns(start, end int) listLayout {
+	layout := listLayout{location: len(m.config.SecretLocations) > 0}
+	for _, item := range m.displayItems[start:end] {
+		if item.Secret == nil {
+			continue
+		}
+		if !item.Secret.ExpireTime.IsZero() {
+			layout.expiry = true
+		}
+		if rotationOverdue(item.Secret) {
+			layout.rotation = true
+		}
+	}
+	if !layout.columns() {
+		return layout
+	}
+	for _, item := range m.displayItems[start:end] {
+		if item.IsFolder {
+			continue
+		}
+		layout.nameWidth = max(layout.nameWidth, lipgloss.Width(item.Name))
+		if layout.location {
+			layout.locationWidth = max(layout.locationWidth, lipgloss.Width(m.locationColumn(item)))
+		}
+		if layout.expiry {
+			layout.expiryWidth = max(layout.expiryWidth, lipgloss.Width(m.expiryColumn(item)))
+		}
+	}
+	return layout
+}
+
+// columns reports whether secrets are rendered with columns after their name
+func (l listLayout) columns() bool {
+	return l.location || l.expiry || l.rotation
+}
+
+// secretColumns renders the name of a secret item followed by its columns
+func (m Model) secretColumns(item *FolderItem, nameStyle lipgloss.Style, layout listLayout) string {
+	line := nameStyle.Width(layout.nameWidth).Render(item.Name)
+	if layout.location {
+		line += "  " + lipgloss.NewStyle().Width(layout.locationWidth).Render(m.locationColumn(item))
+	}
+	if layout.expiry {
+		line += "  " + lipgloss.NewStyle().Width(layout.expiryWidth).Render(m.expiryColumn(item))
+	}
+	if layout.rotation {
+		line += "  " + m.rotationColumn(item)
+	}
+	return line
+}
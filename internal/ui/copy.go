@@ -0,0 +1,369 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+type copyPlannedMsg struct {
+	target gcp.SecretStore
+	plan   *bulk.Plan
+	err    error
+}
+
+type copyDoneMsg struct {
+	project string
+	result  bulk.ApplyResult
+	err     error
+}
+
+// selectionKey identifies a list item in the selection.
+// Folders end with the separator so they never clash with a secret of the same name.
+func (m Model) selectionKey(item *FolderItem) string {
+	if item.IsFolder {
+		return item.FullPath + m.config.FolderSeparator
+	}
+	return item.FullPath
+}
+
+// toggleSelection selects or unselects the item under the cursor and moves down
+func (m Model) toggleSelection() Model {
+	if len(m.displayItems) == 0 {
+		return m
+	}
+	if m.selected == nil {
+		m.selected = make(map[string]bool)
+	}
+	item := m.displayItems[m.cursor]
+	if item.IsFolder && m.groupBy != "" {
+		// Label groups are not name prefixes, select their secrets one by one
+		m.toggleGroup(item)
+	} else {
+		key := m.selectionKey(item)
+		if m.selected[key] {
+			delete(m.selected, key)
+		} else {
+			m.selected[key] = true
+		}
+	}
+	if m.cursor < len(m.displayItems)-1 {
+		m.cursor++
+	}
+	return m
+}
+
+// toggleGroup selects every secret of a label group, or unselects them
+// all when they are already selected
+func (m Model) toggleGroup(item *FolderItem) {
+	secrets := itemSecrets(item)
+	all := true
+	for _, secret := range secrets {
+		if !m.selected[secret.ID()] {
+			all = false
+		}
+	}
+	for _, secret := range secrets {
+		if all {
+			delete(m.selected, secret.ID())
+		} else {
+			m.selected[secret.ID()] = true
+		}
+	}
+}
+
+// selectedSecrets resolves the selection (or, without one, the item under
+// the cursor) to secrets, expanding folders to every secret below them
+func (m Model) selectedSecrets() []gcp.Secret {
+	keys := make([]string, 0, len(m.selected))
+	for key := range m.selected {
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 && len(m.displayItems) > 0 {
+		for _, secret := range itemSecrets(m.displayItems[m.cursor]) {
+			keys = append(keys, secret.ID())
+		}
+	}
+
+	sep := m.config.FolderSeparator
+	seen := make(map[string]bool)
+	var out []gcp.Secret
+	for _, key := range keys {
+		var matches []gcp.Secret
+		if strings.HasSuffix(key, sep) {
+			matches = bulk.UnderFolder(m.secrets, key, sep)
+		} else {
+			for _, secret := range m.secrets {
+				if secret.ID() == key {
+					matches = append(matches, secret)
+				}
+			}
+		}
+		for _, secret := range matches {
+			if !seen[secret.ID()] {
+				seen[secret.ID()] = true
+				out = append(out, secret)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID() < out[j].ID()
+	})
+	return out
+}
+
+// openCopy shows the copy dialog for the selected secrets
+func (m Model) openCopy() (tea.Model, tea.Cmd) {
+	if !m.listComplete() {
+		return m, nil
+	}
+	sources := m.selectedSecrets()
+	if len(sources) == 0 {
+		m.statusMsg = "Nothing to copy"
+		m.statusErr = true
+		return m, nil
+	}
+
+	var targets []string
+	for _, project := range m.config.RecentProjects {
+		if project != m.config.ProjectID {
+			targets = append(targets, project)
+		}
+	}
+	if len(targets) == 0 {
+		m.statusMsg = "No other project to copy to, add one with Ctrl+P"
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.copySources = sources
+	m.copyTargets = targets
+	m.copyTargetIdx = 0
+	m.copySync = true
+	m.copyPlan = nil
+	m.view = ViewCopy
+	return m, nil
+}
+
+func (m Model) updateCopy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	// Confirming a computed plan
+	if m.copyPlan != nil {
+		switch msg.String() {
+		case "y":
+			if !m.copyPlan.HasChanges() {
+				m.closeCopy()
+				m.view = ViewList
+				m.statusMsg = "Nothing to copy, target is up to date"
+				m.statusErr = false
+				return m, nil
+			}
+			m.loading = true
+			m.loadingMsg = "Copying secrets..."
+			return m, m.applyCopy()
+		case "n", "esc":
+			m.closeCopy()
+			m.view = ViewList
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.copyTargetIdx > 0 {
+			m.copyTargetIdx--
+		}
+	case "down", "j":
+		if m.copyTargetIdx < len(m.copyTargets)-1 {
+			m.copyTargetIdx++
+		}
+	case "s", " ":
+		m.copySync = !m.copySync
+	case "enter":
+		m.loading = true
+		m.loadingMsg = "Comparing with " + m.copyTargets[m.copyTargetIdx] + "..."
+		return m, m.planCopy(m.copyTargets[m.copyTargetIdx])
+	case "esc":
+		m.view = ViewList
+	}
+	return m, nil
+}
+
+// planCopy connects to the target project and computes the copy plan
+func (m Model) planCopy(target string) tea.Cmd {
+	sources := m.copySources
+	sync := m.copySync
+	locations := m.config.SecretLocations
+	return m.startOp(operation{bulk: true, run: func(ctx context.Context) (tea.Msg, error) {
+		dst, err := m.openStore(ctx, target)
+		if err != nil {
+			return copyPlannedMsg{err: err}, err
+		}
+		dstSecrets, err := gcp.ListAllSecrets(ctx, dst, "", locations...)
+		if err != nil {
+			if m.auditLogger != nil {
+				m.auditLogger.WithIdentity(dst.UserEmail(), dst.Principal()).LogSecretList(target, 0, audit.ResultFailure, err.Error())
+			}
+			_ = dst.Close()
+			return copyPlannedMsg{err: err}, err
+		}
+		if m.auditLogger != nil {
+			m.auditLogger.WithIdentity(dst.UserEmail(), dst.Principal()).LogSecretList(target, len(dstSecrets), audit.ResultSuccess, "")
+		}
+
+		plan, err := bulk.PlanCopy(ctx, m.client, dst, m.auditLogger, sources, dstSecrets, sync)
+		if err != nil {
+			_ = dst.Close()
+			return copyPlannedMsg{err: err}, err
+		}
+		// Cancelled after the last read: nobody is waiting for the plan
+		if err := ctx.Err(); err != nil {
+			plan.Zero()
+			_ = dst.Close()
+			return copyPlannedMsg{err: err}, err
+		}
+		return copyPlannedMsg{target: dst, plan: plan}, nil
+	}})
+}
+
+func (m Model) applyCopy() tea.Cmd {
+	plan := m.copyPlan
+	target := m.copyTarget
+	return m.startOp(operation{bulk: true, mutates: true, run: func(ctx context.Context) (tea.Msg, error) {
+		result, err := plan.Apply(ctx, target, m.auditLogger)
+		return copyDoneMsg{project: target.ProjectID(), result: result, err: err}, err
+	}})
+}
+
+func (m Model) handleCopyPlanned(msg copyPlannedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Error preparing copy: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.copyTarget = msg.target
+	m.copyPlan = msg.plan
+	return m, nil
+}
+
+func (m Model) handleCopyDone(msg copyDoneMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	m.closeCopy()
+	m.view = ViewList
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Error copying to %s after %d created, %d updated: %v",
+			msg.project, msg.result.Created, msg.result.Updated, msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.selected = nil
+	m.statusMsg = fmt.Sprintf("✓ Copied to %s: %d created, %d updated", msg.project, msg.result.Created, msg.result.Updated)
+	m.statusErr = false
+	return m, nil
+}
+
+// closeCopy wipes the planned values and releases the target connection
+func (m *Model) closeCopy() {
+	if m.copyPlan != nil {
+		m.copyPlan.Zero()
+		m.copyPlan = nil
+	}
+	if m.copyTarget != nil {
+		_ = m.copyTarget.Close()
+		m.copyTarget = nil
+	}
+}
+
+func (m Model) viewCopy() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+	if m.copyPlan != nil {
+		return m.viewCopyPlan()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Copy %d secrets from %s", len(m.copySources), m.config.ProjectID)))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.styles.InputLabel.Render("Target project:"))
+	b.WriteString("\n")
+	for i, project := range m.copyTargets {
+		line := "  📁 " + project
+		if i == m.copyTargetIdx {
+			line = m.styles.ListSelected.Width(55).Render("▶ 📁 " + project)
+		} else {
+			line = m.styles.ListItem.Width(55).Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(m.styles.InputLabel.Render("Mode: "))
+	if m.copySync {
+		b.WriteString(m.styles.StatusSuccess.Render("[Sync]"))
+		b.WriteString(m.styles.SubtleText().Render("  new version only when the value differs"))
+	} else {
+		b.WriteString(m.styles.StatusWarning.Render("[Copy]"))
+		b.WriteString(m.styles.SubtleText().Render("  always add a new version"))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("New secrets keep their labels and replication • values are never displayed"))
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+func (m Model) viewCopyPlan() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Copy to %s", m.copyTarget.ProjectID())))
+	b.WriteString("\n\n")
+
+	// Keep the dialog within the screen
+	maxShow := m.height - 16
+	if maxShow < 5 {
+		maxShow = 5
+	}
+	for i, item := range m.copyPlan.Items {
+		if i == maxShow {
+			b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("  ... and %d more", len(m.copyPlan.Items)-maxShow)))
+			b.WriteString("\n")
+			break
+		}
+		style := m.styles.SubtleText()
+		switch item.Action {
+		case bulk.ActionCreate:
+			style = m.styles.StatusSuccess
+		case bulk.ActionAddVersion:
+			style = m.styles.StatusWarning
+		case bulk.ActionSkip:
+			style = m.styles.StatusError
+		}
+		b.WriteString(style.Render(fmt.Sprintf("  %-45s %s", item.Secret, item.Describe())))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("%d to create, %d to update, %d unchanged, %d skipped\n\n",
+		m.copyPlan.Count(bulk.ActionCreate), m.copyPlan.Count(bulk.ActionAddVersion),
+		m.copyPlan.Count(bulk.ActionUnchanged), m.copyPlan.Count(bulk.ActionSkip)))
+
+	b.WriteString("Press ")
+	b.WriteString(m.styles.FooterKey.Render("y"))
+	b.WriteString(" to apply or ")
+	b.WriteString(m.styles.FooterKey.Render("n"))
+	b.WriteString(" to cancel")
+
+	return m.styles.Dialog.Render(b.String())
+}
@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+type diffDoneMsg struct {
+	diff *bulk.Diff
+	err  error
+}
+
+// openDiff shows the project picker for comparing the current folder with another project
+func (m Model) openDiff() (tea.Model, tea.Cmd) {
+	if !m.listComplete() {
+		return m, nil
+	}
+	var targets []string
+	for _, project := range m.config.RecentProjects {
+		if project != m.config.ProjectID {
+			targets = append(targets, project)
+		}
+	}
+	if len(targets) == 0 {
+		m.statusMsg = "No other project to compare with, add one with Ctrl+P"
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.diffPrefix = ""
+	if len(m.currentPath) > 0 && m.groupBy == "" {
+		m.diffPrefix = strings.Join(m.currentPath, m.config.FolderSeparator) + m.config.FolderSeparator
+	}
+	m.diffTargets = targets
+	m.diffTargetIdx = 0
+	m.diffValues = false
+	m.diffResult = nil
+	m.diffOffset = 0
+	m.view = ViewDiff
+	return m, nil
+}
+
+func (m Model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	// Scrolling through the result
+	if m.diffResult != nil {
+		switch msg.String() {
+		case "up", "k":
+			if m.diffOffset > 0 {
+				m.diffOffset--
+			}
+		case "down", "j":
+			if m.diffOffset < len(m.diffLines())-1 {
+				m.diffOffset++
+			}
+		case "g":
+			m.diffOffset = 0
+		case "G":
+			m.diffOffset = len(m.diffLines()) - 1
+		case "v":
+			if !m.diffValues {
+				m.diffValues = true
+				m.loading = true
+				m.loadingMsg = "Comparing values with " + m.diffResult.ProjectB + "..."
+				return m, m.runDiff(m.diffResult.ProjectB)
+			}
+		case "esc", "h":
+			m.diffResult = nil
+		case "q":
+			m.diffResult = nil
+			m.view = ViewList
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.diffTargetIdx > 0 {
+			m.diffTargetIdx--
+		}
+	case "down", "j":
+		if m.diffTargetIdx < len(m.diffTargets)-1 {
+			m.diffTargetIdx++
+		}
+	case "v", " ":
+		m.diffValues = !m.diffValues
+	case "enter":
+		m.loading = true
+		m.loadingMsg = "Comparing with " + m.diffTargets[m.diffTargetIdx] + "..."
+		return m, m.runDiff(m.diffTargets[m.diffTargetIdx])
+	case "esc":
+		m.view = ViewList
+	}
+	return m, nil
+}
+
+// runDiff lists the other project with the same server filter and compares
+// it with the loaded secrets. Values are only read when value comparison was
+// explicitly enabled.
+func (m Model) runDiff(target string) tea.Cmd {
+	secrets := m.secrets
+	serverFilter := m.serverFilter
+	locations := m.config.SecretLocations
+	prefix := m.diffPrefix
+	compareValues := m.diffValues
+	return m.startOp(operation{bulk: true, run: func(ctx context.Context) (tea.Msg, error) {
+		other, err := m.openStore(ctx, target)
+		if err != nil {
+			return diffDoneMsg{err: err}, err
+		}
+		defer other.Close()
+
+		otherSecrets, err := gcp.ListAllSecrets(ctx, other, serverFilter, locations...)
+		if err != nil {
+			if m.auditLogger != nil {
+				m.auditLogger.WithIdentity(other.UserEmail(), other.Principal()).LogSecretList(target, 0, audit.ResultFailure, err.Error())
+			}
+			return diffDoneMsg{err: err}, err
+		}
+		if m.auditLogger != nil {
+			m.auditLogger.WithIdentity(other.UserEmail(), other.Principal()).LogSecretList(target, len(otherSecrets), audit.ResultSuccess, "")
+		}
+
+		d := bulk.DiffInventories(m.config.ProjectID, secrets, target, otherSecrets, prefix)
+		if compareValues {
+			if err := d.ComparePayloads(ctx, m.client, other, m.auditLogger); err != nil {
+				return diffDoneMsg{err: err}, err
+			}
+		}
+		return diffDoneMsg{diff: d}, nil
+	}})
+}
+
+func (m Model) handleDiffDone(msg diffDoneMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.diffValues = false
+		m.statusMsg = fmt.Sprintf("Error comparing projects: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.diffResult = msg.diff
+	m.diffOffset = 0
+	return m, nil
+}
+
+// diffLines renders the diff result as styled lines for scrolling
+func (m Model) diffLines() []string {
+	d := m.diffResult
+	var lines []string
+
+	lines = append(lines, m.styles.InputLabel.Render(fmt.Sprintf("Only in %s (%d)", d.ProjectA, len(d.OnlyA))))
+	for _, name := range d.OnlyA {
+		lines = append(lines, m.styles.StatusError.Render("  - "+name))
+	}
+	lines = append(lines, "", m.styles.InputLabel.Render(fmt.Sprintf("Only in %s (%d)", d.ProjectB, len(d.OnlyB))))
+	for _, name := range d.OnlyB {
+		lines = append(lines, m.styles.StatusSuccess.Render("  + "+name))
+	}
+	lines = append(lines, "", m.styles.InputLabel.Render(fmt.Sprintf("In both (%d)", len(d.Both))))
+	for _, e := range d.Both {
+		style := m.styles.SubtleText()
+		var notes []string
+		for _, c := range e.Labels {
+			notes = append(notes, c.String())
+		}
+		if e.Payload != bulk.PayloadNotCompared {
+			notes = append(notes, "payload "+string(e.Payload))
+		}
+		if len(e.Labels) > 0 || (e.Payload != bulk.PayloadNotCompared && e.Payload != bulk.PayloadSame) {
+			style = m.styles.StatusWarning
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("  = %-45s %s", e.Name, strings.Join(notes, " "))))
+	}
+	return lines
+}
+
+func (m Model) viewDiff() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+	if m.diffResult != nil {
+		return m.viewDiffResult()
+	}
+
+	var b strings.Builder
+
+	scope := m.diffPrefix
+	if scope == "" {
+		scope = "all secrets"
+	}
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Compare %s (%s)", m.config.ProjectID, scope)))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.styles.InputLabel.Render("Compare with:"))
+	b.WriteString("\n")
+	for i, project := range m.diffTargets {
+		line := "  📁 " + project
+		if i == m.diffTargetIdx {
+			line = m.styles.ListSelected.Width(55).Render("▶ 📁 " + project)
+		} else {
+			line = m.styles.ListItem.Width(55).Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(m.styles.InputLabel.Render("Values: "))
+	if m.diffValues {
+		b.WriteString(m.styles.StatusWarning.Render("[Compare]"))
+		b.WriteString(m.styles.SubtleText().Render("  reads latest versions in both projects (audited)"))
+	} else {
+		b.WriteString(m.styles.StatusSuccess.Render("[Skip]"))
+		b.WriteString(m.styles.SubtleText().Render("  names and labels only"))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("Values are compared by hash and never displayed"))
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+func (m Model) viewDiffResult() string {
+	var b strings.Builder
+
+	d := m.diffResult
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("🔀 %s ↔ %s", d.ProjectA, d.ProjectB)))
+	b.WriteString("\n")
+	if m.diffPrefix != "" {
+		b.WriteString(m.styles.SubtleText().Render("Scope: " + m.diffPrefix))
+		b.WriteString("\n")
+	}
+	if m.serverFilter != "" {
+		b.WriteString(m.styles.SubtleText().Render("Server filter: " + m.serverFilter))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	lines := m.diffLines()
+	visibleLines := m.height - 12
+	if visibleLines < 5 {
+		visibleLines = 5
+	}
+	endIdx := m.diffOffset + visibleLines
+	if endIdx > len(lines) {
+		endIdx = len(lines)
+	}
+	for _, line := range lines[m.diffOffset:endIdx] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	status := "Projects differ"
+	if d.InSync() {
+		status = "✓ In sync"
+	}
+	if !m.diffValues {
+		status += " • values not compared, press v to compare"
+	}
+	b.WriteString(m.styles.SubtleText().Render(status))
+
+	return b.String()
+}
@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+type expiryUpdatedMsg struct {
+	secretName string
+	oldExpiry  time.Time
+	expiry     time.Time
+	secret     *gcp.Secret // Secret as stored after the update or the conflict
+	err        error
+}
+
+func newExpiryInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "never, or 90d, 36h, 2030-01-31"
+	input.CharLimit = 40
+	input.Width = 46
+	return input
+}
+
+// formatExpiry renders an expiration time in the format used for timestamps
+func formatExpiry(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// expiryWarning reports whether a secret expires within the configured
+// warning window
+func (m Model) expiryWarning(secret *gcp.Secret) bool {
+	window := time.Duration(m.config.Expiry.WarnDays) * 24 * time.Hour
+	return secret.ExpiresWithin(window, time.Now())
+}
+
+// expiryColumn renders the time left before the secret behind a list item
+// expires, highlighted when that is within the warning window
+func (m Model) expiryColumn(item *FolderItem) string {
+	if item.Secret == nil || item.Secret.ExpireTime.IsZero() {
+		return ""
+	}
+	left := gcp.FormatRemaining(item.Secret.ExpireTime, time.Now())
+	if m.expiryWarning(item.Secret) {
+		return m.styles.StatusError.Render("⚠ " + left)
+	}
+	return m.styles.SubtleText().Render("⏳ " + left)
+}
+
+// expiryDetail renders the expiration of the selected secret for the detail view
+func (m Model) expiryDetail() string {
+	secret := m.selectedSecret
+	if secret.ExpireTime.IsZero() {
+		return m.styles.SubtleText().Render("never")
+	}
+	line := fmt.Sprintf("%s (in %s)", formatExpiry(secret.ExpireTime), gcp.FormatRemaining(secret.ExpireTime, time.Now()))
+	if m.expiryWarning(secret) {
+		return m.styles.StatusError.Render("⚠ " + line)
+	}
+	return m.styles.DetailValue.Render(line)
+}
+
+// createExpiryField is the index of the expiry field of the create dialog,
+// which follows the KMS key fields
+func (m Model) createExpiryField() int {
+	return 3 + len(m.createLocations())
+}
+
+// focusCreateExtra focuses the field after the location selector that has
+// focus: a KMS key, the expiry or the regional location selector
+func (m *Model) focusCreateExtra() tea.Cmd {
+	if m.createFocus == m.createRegionalField() {
+		return nil
+	}
+	if m.createFocus == m.createExpiryField() {
+		m.createExpiryInput.Focus()
+		return textinput.Blink
+	}
+	return m.focusCreateKMS()
+}
+
+func (m *Model) blurCreateExtra() {
+	if m.createFocus == m.createRegionalField() {
+		return
+	}
+	if m.createFocus == m.createExpiryField() {
+		m.createExpiryInput.Blur()
+		return
+	}
+	m.blurCreateKMS()
+}
+
+func (m Model) updateCreateExtra(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.createFocus == m.createRegionalField() {
+		return m, nil
+	}
+	if m.createFocus == m.createExpiryField() {
+		var cmd tea.Cmd
+		m.createExpiryInput, cmd = m.createExpiryInput.Update(msg)
+		return m, cmd
+	}
+	return m.updateCreateKMS(msg)
+}
+
+// createExpireTime parses the expiry entered in the create dialog, zero
+// when the secret should not expire
+func (m Model) createExpireTime() (time.Time, error) {
+	value := strings.TrimSpace(m.createExpiryInput.Value())
+	if value == "" || value == "never" {
+		return time.Time{}, nil
+	}
+	return gcp.ParseExpiry(value, time.Now())
+}
+
+func (m Model) viewCreateExpiry() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.InputLabel.Render("Expires:"))
+	b.WriteString(m.styles.SubtleText().Render("  TTL or date, empty to keep the secret until deleted"))
+	b.WriteString("\n")
+	inputStyle := m.styles.Input
+	if m.createFocus == m.createExpiryField() {
+		inputStyle = m.styles.InputFocused
+	}
+	b.WriteString(inputStyle.Width(50).Render(m.createExpiryInput.View()))
+	return b.String()
+}
+
+// openExpiry shows the dialog changing the expiration of the selected secret
+func (m Model) openExpiry() (tea.Model, tea.Cmd) {
+	if m.selectedSecret == nil {
+		return m, nil
+	}
+	m.expiryInput = newExpiryInput()
+	m.expiryInput.SetValue(expiryInputValue(m.selectedSecret.ExpireTime))
+	m.expiryInput.Focus()
+	m.view = ViewExpiry
+	return m, textinput.Blink
+}
+
+// expiryInputValue renders an expiration time for editing, as RFC 3339
+func expiryInputValue(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (m Model) updateExpiry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		value := strings.TrimSpace(m.expiryInput.Value())
+		if value == "never" {
+			value = ""
+		}
+		if value == expiryInputValue(m.selectedSecret.ExpireTime) {
+			m.view = ViewDetail
+			return m, nil
+		}
+		var expiry time.Time
+		if value != "" {
+			var err error
+			expiry, err = gcp.ParseExpiry(value, time.Now())
+			if err != nil {
+				m.statusMsg = err.Error()
+				m.statusErr = true
+				return m, nil
+			}
+		}
+		m.loading = true
+		m.loadingMsg = "Updating expiration..."
+		secret := *m.selectedSecret
+		return m, m.updateExpiration(secret.ID(), secret.ExpireTime, expiry, secret.Etag)
+	case "ctrl+d":
+		m.expiryInput.SetValue("")
+		return m, nil
+	case "esc":
+		m.view = ViewDetail
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.expiryInput, cmd = m.expiryInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateExpiration(secretName string, oldExpiry, expiry time.Time, etag string) tea.Cmd {
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		msg := expiryUpdatedMsg{secretName: secretName, oldExpiry: oldExpiry, expiry: expiry}
+		msg.err = m.client.UpdateSecretExpiration(ctx, secretName, expiry, etag)
+		if msg.err == nil || gcp.IsConflict(msg.err) {
+			// Pick up the new etag, or what someone else changed
+			msg.secret, _ = m.client.GetSecret(ctx, secretName)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) handleExpiryUpdated(msg expiryUpdatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		m.auditLogger.LogExpiryUpdate(m.config.ProjectID, msg.secretName,
+			formatExpiry(msg.oldExpiry), formatExpiry(msg.expiry), result, errMsg)
+	}
+
+	if m.selectedSecret != nil && m.selectedSecret.ID() == msg.secretName {
+		if msg.err == nil {
+			m.selectedSecret.ExpireTime = msg.expiry
+			m.selectedSecret.Etag = ""
+		}
+		if msg.secret != nil {
+			m.selectedSecret.ExpireTime = msg.secret.ExpireTime
+			m.selectedSecret.Etag = msg.secret.Etag
+		}
+	}
+
+	if msg.err != nil {
+		if gcp.IsConflict(msg.err) && msg.secret != nil {
+			// Stay in the dialog so the edit can be applied to the reloaded secret
+			m.statusMsg = fmt.Sprintf("Secret was changed by someone else, it now expires: %s. Press Enter to apply anyway",
+				formatExpiry(msg.secret.ExpireTime))
+		} else {
+			m.statusMsg = fmt.Sprintf("Error updating expiration: %v", msg.err)
+		}
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.view = ViewDetail
+	m.statusMsg = "✓ Expiration removed"
+	if !msg.expiry.IsZero() {
+		m.statusMsg = "✓ Secret expires " + formatExpiry(msg.expiry)
+	}
+	m.statusErr = false
+	return m, nil
+}
+
+func (m Model) viewExpiry() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render("Expiration of " + m.selectedSecret.ID()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Currently: "))
+	b.WriteString(m.expiryDetail())
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Expires:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.InputFocused.Width(50).Render(m.expiryInput.View()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("A TTL from now (90d, 36h, 1d12h), a date (2030-01-31) or an RFC 3339 time"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Leave empty to remove the expiration"))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.StatusWarning.Render("Secret Manager deletes the secret and all its versions when it expires"))
+
+	return m.styles.Dialog.Render(b.String())
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/bulk"
+	"github.com/theburrowhub/go-secret/internal/secretfile"
+)
+
+type exportDoneMsg struct {
+	path   string
+	result bulk.ExportResult
+	err    error
+}
+
+// openExport shows the export dialog for a folder
+func (m Model) openExport(folder string) (tea.Model, tea.Cmd) {
+	if !m.listComplete() {
+		return m, nil
+	}
+	if m.groupBy != "" {
+		m.statusMsg = "Export works on name folders, press b to browse by folder"
+		m.statusErr = true
+		return m, nil
+	}
+	m.exportFolder = folder
+	m.exportFormatIdx = 0
+	m.exportPathInput = textinput.New()
+	m.exportPathInput.Placeholder = "path/to/file"
+	m.exportPathInput.CharLimit = 255
+	m.exportPathInput.SetValue(m.defaultExportPath())
+	m.exportPathInput.Focus()
+	m.view = ViewExport
+	return m, textinput.Blink
+}
+
+// defaultExportPath suggests a file name in the working directory for the folder and format
+func (m Model) defaultExportPath() string {
+	format := secretfile.Formats[m.exportFormatIdx]
+	name := secretfile.ManifestName(m.exportFolder, m.config.FolderSeparator)
+	if format == secretfile.FormatK8s {
+		name += "-secret"
+	}
+	return name + format.Extension()
+}
+
+func (m Model) updateExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "left", "right":
+		// Keep a custom path, but follow the format while the suggestion is untouched
+		suggested := m.exportPathInput.Value() == m.defaultExportPath()
+		n := len(secretfile.Formats)
+		if msg.String() == "left" {
+			m.exportFormatIdx = (m.exportFormatIdx + n - 1) % n
+		} else {
+			m.exportFormatIdx = (m.exportFormatIdx + 1) % n
+		}
+		if suggested {
+			m.exportPathInput.SetValue(m.defaultExportPath())
+		}
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.exportPathInput.Value())
+		if path == "" {
+			m.statusMsg = "File path is required"
+			m.statusErr = true
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = "Exporting secrets..."
+		return m, m.exportSecrets(m.exportFolder, path, secretfile.Formats[m.exportFormatIdx])
+	case "esc":
+		m.view = ViewList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.exportPathInput, cmd = m.exportPathInput.Update(msg)
+	return m, cmd
+}
+
+// exportSecrets writes every secret under folder to path; each read is audited
+func (m Model) exportSecrets(folder, path string, format secretfile.Format) tea.Cmd {
+	secrets := m.secrets
+	naming := bulk.EnvNaming{
+		Folder:    folder,
+		Separator: m.config.FolderSeparator,
+		Rule:      bulk.NamingRule(m.config.Env.Naming),
+		Prefix:    m.config.Env.Prefix,
+	}
+	opts := secretfile.ManifestOptions{Name: secretfile.ManifestName(folder, m.config.FolderSeparator)}
+	return m.startOp(operation{bulk: true, run: func(ctx context.Context) (tea.Msg, error) {
+		data, result, err := bulk.Export(ctx, m.client, m.auditLogger, secrets, naming, format, opts)
+		if err != nil {
+			return exportDoneMsg{path: path, err: err}, err
+		}
+		defer func() {
+			for i := range data {
+				data[i] = 0
+			}
+		}()
+
+		f, err := secretfile.Create(path)
+		if err != nil {
+			return exportDoneMsg{path: path, err: err}, nil
+		}
+		if _, err := f.Write(data); err != nil {
+			_ = f.Close()
+			return exportDoneMsg{path: path, err: fmt.Errorf("failed to write %s: %w", path, err)}, nil
+		}
+		if err := f.Close(); err != nil {
+			return exportDoneMsg{path: path, err: fmt.Errorf("failed to write %s: %w", path, err)}, nil
+		}
+		return exportDoneMsg{path: path, result: result}, nil
+	}})
+}
+
+func (m Model) handleExportDone(msg exportDoneMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Error exporting: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.view = ViewList
+	m.statusMsg = fmt.Sprintf("✓ Exported %d secrets to %s", msg.result.Exported, msg.path)
+	m.statusErr = false
+	if len(msg.result.Skipped) > 0 {
+		m.statusMsg += fmt.Sprintf(" (%d skipped, no enabled version)", len(msg.result.Skipped))
+	}
+	return m, nil
+}
+
+func (m Model) viewExport() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(
+		fmt.Sprintf("Export %s", m.exportFolder+m.config.FolderSeparator),
+	))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.styles.InputLabel.Render("Format:"))
+	b.WriteString(m.styles.FooterKey.Render(" (←/→ to change)"))
+	b.WriteString("\n")
+	for i, format := range secretfile.Formats {
+		option := exportFormatLabel(format)
+		if i == m.exportFormatIdx {
+			option = m.styles.ListSelected.Render("▶ " + option)
+		} else {
+			option = m.styles.SubtleText().Render("  " + option)
+		}
+		b.WriteString(option)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(m.styles.InputLabel.Render("File:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.InputFocused.Width(50).Render(m.exportPathInput.View()))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.styles.SubtleText().Render("Latest enabled version of each secret • file is created with 0600 permissions"))
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+// exportFormatLabel describes a format in the export dialog
+func exportFormatLabel(format secretfile.Format) string {
+	switch format {
+	case secretfile.FormatDotenv:
+		return ".env (KEY=value)"
+	case secretfile.FormatJSON:
+		return "JSON object"
+	case secretfile.FormatYAML:
+		return "YAML map"
+	default:
+		return "Kubernetes Secret manifest"
+	}
+}
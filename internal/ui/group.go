@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/filter"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// Group names for secrets without the label or with an empty value.
+// Label values cannot contain parentheses, so these never clash.
+const (
+	groupMissing = "(none)"
+	groupEmpty   = "(empty)"
+)
+
+// buildLabelTree groups secrets into one folder per value of the groupBy
+// label, listing each secret under its full name
+func (m *Model) buildLabelTree() {
+	m.folderTree = &FolderItem{
+		Name:     "",
+		Children: make(map[string]*FolderItem),
+	}
+
+	for i := range m.secrets {
+		secret := &m.secrets[i]
+		group := groupMissing
+		if value, ok := secret.Labels[m.groupBy]; ok {
+			group = value
+			if value == "" {
+				group = groupEmpty
+			}
+		}
+
+		folder, exists := m.folderTree.Children[group]
+		if !exists {
+			folder = &FolderItem{
+				Name:     group,
+				FullPath: group,
+				IsFolder: true,
+				Children: make(map[string]*FolderItem),
+			}
+			m.folderTree.Children[group] = folder
+		}
+		folder.Children[secret.ID()] = &FolderItem{
+			Name:     secret.Name,
+			FullPath: secret.ID(),
+			Secret:   secret,
+			Children: make(map[string]*FolderItem),
+			Depth:    1,
+		}
+	}
+}
+
+// itemSecrets returns every secret at or below a tree item
+func itemSecrets(item *FolderItem) []*gcp.Secret {
+	if !item.IsFolder {
+		if item.Secret == nil {
+			return nil
+		}
+		return []*gcp.Secret{item.Secret}
+	}
+	var out []*gcp.Secret
+	for _, child := range item.Children {
+		out = append(out, itemSecrets(child)...)
+	}
+	return out
+}
+
+// itemMatches applies the filter query to a displayed item. Name and label
+// terms match a folder when any secret below it matches.
+func itemMatches(item *FolderItem, q filter.Query) bool {
+	if !q.MatchText(item.Name) {
+		return false
+	}
+	if !q.HasSecretTerms() {
+		return true
+	}
+	for _, secret := range itemSecrets(item) {
+		if q.MatchSecret(secret.Name, secret.Labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// labelKeys returns every label key used by the loaded secrets, sorted
+func (m Model) labelKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, secret := range m.secrets {
+		for key := range secret.Labels {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// openGroupBy shows the picker for the list grouping
+func (m Model) openGroupBy() (tea.Model, tea.Cmd) {
+	m.groupKeys = m.labelKeys()
+	m.groupCursor = 0
+	for i, key := range m.groupKeys {
+		if key == m.groupBy {
+			m.groupCursor = i + 1
+		}
+	}
+	m.view = ViewGroupBy
+	return m, nil
+}
+
+func (m Model) updateGroupBy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.groupCursor > 0 {
+			m.groupCursor--
+		}
+	case "down", "j":
+		if m.groupCursor < len(m.groupKeys) {
+			m.groupCursor++
+		}
+	case "enter":
+		m.groupBy = ""
+		if m.groupCursor > 0 {
+			m.groupBy = m.groupKeys[m.groupCursor-1]
+		}
+		m.currentPath = []string{}
+		m.selected = nil
+		m.cursor = 0
+		m.buildFolderTree()
+		m.updateDisplayItems()
+		m.view = ViewList
+	case "esc":
+		m.view = ViewList
+	}
+	return m, nil
+}
+
+func (m Model) viewGroupBy() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render("Browse By"))
+	b.WriteString("\n\n")
+
+	options := []string{"📁 Folders (" + m.config.FolderSeparator + ")"}
+	for _, key := range m.groupKeys {
+		options = append(options, "🏷️  Label: "+key)
+	}
+	for i, option := range options {
+		line := "  " + option
+		if i == m.groupCursor {
+			line = m.styles.ListSelected.Width(45).Render("▶ " + option)
+		} else {
+			line = m.styles.ListItem.Width(45).Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if len(m.groupKeys) == 0 {
+		b.WriteString("\n")
+		b.WriteString(m.styles.SubtleText().Render("No labels found on the loaded secrets"))
+	}
+
+	return m.styles.Dialog.Render(b.String())
+}
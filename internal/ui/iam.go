@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// detailTab is a tab of the detail view
+type detailTab int
+
+const (
+	detailOverview detailTab = iota
+	detailIAM
+)
+
+type iamPolicyLoadedMsg struct {
+	secretName string
+	policy     *gcp.IAMPolicy
+	err        error
+}
+
+type iamUpdatedMsg struct {
+	secretName string
+	changes    []gcp.IAMChange
+	policy     *gcp.IAMPolicy // Policy as stored after the update or the conflict
+	err        error
+}
+
+// iamRow is a member of a role, as listed on the IAM tab
+type iamRow struct {
+	role      string
+	member    string
+	condition *gcp.IAMCondition
+}
+
+// iamRows lists the members of the loaded policy, sorted by role and member
+func (m Model) iamRows() []iamRow {
+	if m.iamPolicy == nil {
+		return nil
+	}
+	bindings := append([]gcp.IAMBinding(nil), m.iamPolicy.Bindings...)
+	sort.SliceStable(bindings, func(i, j int) bool { return bindings[i].Role < bindings[j].Role })
+	var rows []iamRow
+	for _, binding := range bindings {
+		members := append([]string(nil), binding.Members...)
+		sort.Strings(members)
+		for _, member := range members {
+			rows = append(rows, iamRow{role: binding.Role, member: member, condition: binding.Condition})
+		}
+	}
+	return rows
+}
+
+// toggleDetailTab switches between the overview and the IAM policy of the
+// selected secret, loading the policy the first time it is shown
+func (m Model) toggleDetailTab() (tea.Model, tea.Cmd) {
+	if m.detailTab == detailIAM {
+		m.detailTab = detailOverview
+		return m, nil
+	}
+	if !m.checkPermission(detailPermissions, "tab") {
+		return m, nil
+	}
+	m.detailTab = detailIAM
+	if m.iamPolicy != nil && m.iamSecret == m.selectedSecret.ID() {
+		return m, nil
+	}
+	return m, m.reloadIAMPolicy()
+}
+
+func (m *Model) reloadIAMPolicy() tea.Cmd {
+	m.iamPolicy = nil
+	m.iamSecret = m.selectedSecret.ID()
+	m.iamCursor = 0
+	m.loading = true
+	m.loadingMsg = "Loading IAM policy..."
+	return m.loadIAMPolicy(m.iamSecret)
+}
+
+func (m Model) loadIAMPolicy(secretName string) tea.Cmd {
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		policy, err := m.client.GetIamPolicy(ctx, secretName)
+		return iamPolicyLoadedMsg{secretName: secretName, policy: policy, err: err}, err
+	}})
+}
+
+func (m Model) handleIAMPolicyLoaded(msg iamPolicyLoadedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.secretName != m.iamSecret {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Error loading IAM policy: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.iamPolicy = msg.policy
+	if rows := m.iamRows(); m.iamCursor >= len(rows) {
+		m.iamCursor = max(len(rows)-1, 0)
+	}
+	return m, nil
+}
+
+func (m Model) updateIAMTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.checkPermission(iamPermissions, msg.String()) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "up", "k":
+		if m.iamCursor > 0 {
+			m.iamCursor--
+		}
+	case "down", "j":
+		if m.iamCursor < len(m.iamRows())-1 {
+			m.iamCursor++
+		}
+	case "ctrl+r":
+		return m, m.reloadIAMPolicy()
+	case "a":
+		if m.iamPolicy == nil {
+			return m, nil
+		}
+		m.iamInput = textinput.New()
+		m.iamInput.Placeholder = "alice@example.com or app@project.iam.gserviceaccount.com"
+		m.iamInput.CharLimit = 254
+		m.iamInput.Width = 56
+		m.iamInput.Focus()
+		m.view = ViewIAMGrant
+		return m, textinput.Blink
+	case "x":
+		rows := m.iamRows()
+		if len(rows) == 0 {
+			return m, nil
+		}
+		row := rows[m.iamCursor]
+		if row.role != gcp.SecretAccessorRole || row.condition != nil {
+			m.statusMsg = fmt.Sprintf("Only unconditional %s grants can be revoked here", gcp.SecretAccessorRole)
+			m.statusErr = true
+			return m, nil
+		}
+		return m.confirmIAMChanges(m.iamPolicy.WithoutMember(row.role, row.member))
+	case "esc", "backspace", "h":
+		m.detailTab = detailOverview
+		return m.updateDetail(msg)
+	case "q":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) updateIAMGrant(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		member, err := gcp.ParseMember(m.iamInput.Value())
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		if m.iamPolicy.HasMember(gcp.SecretAccessorRole, member) {
+			m.statusMsg = fmt.Sprintf("%s already has %s", member, gcp.SecretAccessorRole)
+			m.statusErr = true
+			return m, nil
+		}
+		m.iamInput.Blur()
+		m.statusMsg = ""
+		return m.confirmIAMChanges(m.iamPolicy.WithMember(gcp.SecretAccessorRole, member))
+	case "esc":
+		m.iamInput.Blur()
+		m.view = ViewDetail
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.iamInput, cmd = m.iamInput.Update(msg)
+	return m, cmd
+}
+
+// confirmIAMChanges shows the diff between the loaded policy and policy
+// before it is set
+func (m Model) confirmIAMChanges(policy gcp.IAMPolicy) (tea.Model, tea.Cmd) {
+	m.iamPending = policy
+	m.iamChanges = gcp.DiffIAM(*m.iamPolicy, policy)
+	m.view = ViewIAMConfirm
+	return m, nil
+}
+
+func (m Model) updateIAMConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+	switch msg.String() {
+	case "y":
+		m.loading = true
+		m.loadingMsg = "Updating IAM policy..."
+		return m, m.setIAMPolicy(m.iamSecret, m.iamPending, m.iamChanges)
+	case "n", "esc":
+		m.view = ViewDetail
+	}
+	return m, nil
+}
+
+func (m Model) setIAMPolicy(secretName string, policy gcp.IAMPolicy, changes []gcp.IAMChange) tea.Cmd {
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		msg := iamUpdatedMsg{secretName: secretName, changes: changes}
+		msg.err = m.client.SetIamPolicy(ctx, secretName, policy)
+		if msg.err == nil || gcp.IsConflict(msg.err) {
+			// Pick up the new etag, or what someone else changed
+			msg.policy, _ = m.client.GetIamPolicy(ctx, secretName)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) handleIAMUpdated(msg iamUpdatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	lines := make([]string, len(msg.changes))
+	for i, change := range msg.changes {
+		lines[i] = change.String()
+	}
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		m.auditLogger.LogIAMUpdate(m.config.ProjectID, msg.secretName, strings.Join(lines, "; "), result, errMsg)
+	}
+
+	if msg.policy != nil && msg.secretName == m.iamSecret {
+		m.iamPolicy = msg.policy
+		if rows := m.iamRows(); m.iamCursor >= len(rows) {
+			m.iamCursor = max(len(rows)-1, 0)
+		}
+	}
+
+	if msg.err != nil {
+		if gcp.IsConflict(msg.err) && msg.policy != nil && msg.secretName == m.iamSecret {
+			// Replay the edit on the reloaded policy and ask again
+			policy := *msg.policy
+			for _, change := range msg.changes {
+				if change.Removed {
+					policy = policy.WithoutMember(change.Role, change.Member)
+				} else {
+					policy = policy.WithMember(change.Role, change.Member)
+				}
+			}
+			if len(gcp.DiffIAM(*msg.policy, policy)) > 0 {
+				m.statusMsg = "IAM policy was changed by someone else and was reloaded. Review the changes again"
+				m.statusErr = true
+				return m.confirmIAMChanges(policy)
+			}
+		}
+		m.view = ViewDetail
+		m.statusMsg = fmt.Sprintf("Error updating IAM policy: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.view = ViewDetail
+	m.statusMsg = "✓ IAM policy updated: " + strings.Join(lines, ", ")
+	m.statusErr = false
+	// The change may have granted or revoked permissions of the caller
+	return m, m.testPermissions(msg.secretName)
+}
+
+// detailTabs renders the tab bar of the detail view
+func (m Model) detailTabs() string {
+	tabs := []string{"Overview", "IAM"}
+	var out []string
+	for i, tab := range tabs {
+		if detailTab(i) == m.detailTab {
+			out = append(out, m.styles.ListSelected.Render(tab))
+		} else {
+			out = append(out, m.styles.ListItem.Render(tab))
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func (m Model) viewIAMTab() string {
+	var b strings.Builder
+
+	if m.iamPolicy == nil {
+		b.WriteString(m.styles.SubtleText().Render("IAM policy not loaded, press Ctrl+R to retry"))
+		return b.String()
+	}
+
+	rows := m.iamRows()
+	if len(rows) == 0 {
+		b.WriteString(m.styles.SubtleText().Render("No bindings on this secret, access is inherited from the project"))
+		b.WriteString("\n")
+	}
+	role := ""
+	for i, row := range rows {
+		if row.role != role {
+			if role != "" {
+				b.WriteString("\n")
+			}
+			role = row.role
+			b.WriteString(m.styles.ListTitle.MarginBottom(0).Render(row.role))
+			b.WriteString("\n")
+		}
+		line := row.member
+		if row.condition != nil {
+			title := row.condition.Title
+			if title == "" {
+				title = row.condition.Expression
+			}
+			line += "  " + m.styles.StatusWarning.Render("if "+title)
+		}
+		if i == m.iamCursor {
+			line = m.styles.ListSelected.Width(m.width - 6).Render(line)
+		} else {
+			line = m.styles.ListItem.Width(m.width - 6).Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("a grants and x revokes %s. Changing the policy needs the Secret Manager Admin role.", gcp.SecretAccessorRole)))
+	return b.String()
+}
+
+func (m Model) viewIAMGrant() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Grant access to %s", m.iamSecret)))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Role: "))
+	b.WriteString(m.styles.DetailValue.Render(gcp.SecretAccessorRole))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("User or service account email:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.InputFocused.Width(60).Render(m.iamInput.View()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("*.gserviceaccount.com addresses are granted as serviceAccount:, others as user:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Prefix group: or domain: to grant a group or a whole domain"))
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+func (m Model) viewIAMConfirm() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Update IAM policy of %s", m.iamSecret)))
+	b.WriteString("\n\n")
+	for _, change := range m.iamChanges {
+		if change.Removed {
+			b.WriteString(m.styles.StatusError.Render(change.String()))
+		} else {
+			b.WriteString(m.styles.StatusSuccess.Render(change.String()))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Other bindings, including conditional ones, are kept as they are"))
+	b.WriteString("\n\n")
+	b.WriteString("Apply these changes? (y/n)")
+
+	return m.styles.Dialog.Render(b.String())
+}
@@ -109,8 +109,9 @@ func DefaultKeyMap() KeyMap {
 
 // FooterBinding represents a key binding shown in the footer
 type FooterBinding struct {
-	Key  string
-	Desc string
+	Key      string
+	Desc     string
+	Disabled bool // Greyed out, e.g. for lack of permission
 }
 
 // ListViewBindings returns the keybindings for the list view
@@ -123,6 +124,13 @@ func ListViewBindings() []FooterBinding {
 		{Key: "/", Desc: "filter"},
 		{Key: "n", Desc: "new"},
 		{Key: "d", Desc: "delete"},
+		{Key: "e", Desc: "export folder"},
+		{Key: "Space", Desc: "select"},
+		{Key: "C", Desc: "copy to project"},
+		{Key: "D", Desc: "diff project"},
+		{Key: "b", Desc: "browse by"},
+		{Key: "F", Desc: "server filter"},
+		{Key: "P", Desc: "permissions"},
 		{Key: "^R", Desc: "refresh"},
 		{Key: "^S", Desc: "settings"},
 		{Key: "^P", Desc: "project"},
@@ -138,6 +146,15 @@ func DetailViewBindings() []FooterBinding {
 		{Key: "c", Desc: "copy"},
 		{Key: "a", Desc: "add version"},
 		{Key: "g", Desc: "generate"},
+		{Key: "x/e", Desc: "disable/enable"},
+		{Key: "X", Desc: "destroy"},
+		{Key: "L", Desc: "labels"},
+		{Key: "N", Desc: "annotations"},
+		{Key: "t", Desc: "expiry"},
+		{Key: "R", Desc: "rotation"},
+		{Key: "A", Desc: "alias"},
+		{Key: "Tab", Desc: "IAM"},
+		{Key: "P", Desc: "permissions"},
 		{Key: "d", Desc: "delete"},
 		{Key: "Esc/h", Desc: "back"},
 		{Key: "^S", Desc: "settings"},
@@ -160,6 +177,7 @@ func CreateViewBindings() []FooterBinding {
 	return []FooterBinding{
 		{Key: "Tab", Desc: "fields"},
 		{Key: "←/→", Desc: "location"},
+		{Key: "Space", Desc: "toggle region"},
 		{Key: "^E", Desc: "editor"},
 		{Key: "^S/Enter", Desc: "submit"},
 		{Key: "Esc", Desc: "cancel"},
@@ -174,11 +192,153 @@ func ConfirmViewBindings() []FooterBinding {
 	}
 }
 
+// DestroyVersionBindings returns the keybindings for the destroy version dialog
+func DestroyVersionBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Enter", Desc: "destroy"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// LabelsViewBindings returns the keybindings for the label editor
+func LabelsViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Tab/↑↓", Desc: "fields"},
+		{Key: "^N", Desc: "add"},
+		{Key: "^D", Desc: "remove"},
+		{Key: "^S/Enter", Desc: "save"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// LabelsConflictBindings returns the keybindings for the label conflict dialog
+func LabelsConflictBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "r", Desc: "reload and reapply"},
+		{Key: "n/Esc", Desc: "discard"},
+	}
+}
+
+// GroupByBindings returns the keybindings for the browse-by picker
+func GroupByBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "↑↓/jk", Desc: "navigate"},
+		{Key: "Enter", Desc: "select"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// ServerFilterBindings returns the keybindings for the server-side filter dialog
+func ServerFilterBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Enter", Desc: "apply and reload"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// ExpiryViewBindings returns the keybindings for the expiration dialog
+func ExpiryViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Enter", Desc: "apply"},
+		{Key: "^D", Desc: "clear"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// RotationViewBindings returns the keybindings for the rotation dialog
+func RotationViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Tab/↑↓", Desc: "fields"},
+		{Key: "Enter", Desc: "apply"},
+		{Key: "^D", Desc: "stop rotating"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// AliasViewBindings returns the keybindings for the alias dialog
+func AliasViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Enter", Desc: "assign"},
+		{Key: "^D", Desc: "remove"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// IAMTabBindings returns the keybindings for the IAM tab of the detail view
+func IAMTabBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "↑↓/jk", Desc: "members"},
+		{Key: "a", Desc: "grant access"},
+		{Key: "x", Desc: "revoke access"},
+		{Key: "^R", Desc: "reload"},
+		{Key: "Tab", Desc: "overview"},
+		{Key: "Esc/h", Desc: "back"},
+		{Key: "q", Desc: "quit"},
+	}
+}
+
+// IAMGrantBindings returns the keybindings for the grant access dialog
+func IAMGrantBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Enter", Desc: "review"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// PermissionsViewBindings returns the keybindings for the permissions panel
+func PermissionsViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "^R", Desc: "test again"},
+		{Key: "Esc", Desc: "close"},
+	}
+}
+
+// ExportViewBindings returns the keybindings for the export dialog
+func ExportViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "←/→", Desc: "format"},
+		{Key: "Enter", Desc: "export"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// CopyViewBindings returns the keybindings for the copy dialog
+func CopyViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "↑↓/jk", Desc: "target"},
+		{Key: "s", Desc: "sync/copy"},
+		{Key: "Enter", Desc: "review"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// DiffViewBindings returns the keybindings for the diff project picker
+func DiffViewBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "↑↓/jk", Desc: "project"},
+		{Key: "v", Desc: "compare values"},
+		{Key: "Enter", Desc: "compare"},
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// DiffResultBindings returns the keybindings for the diff result
+func DiffResultBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "↑↓/jk", Desc: "scroll"},
+		{Key: "g/G", Desc: "top/bottom"},
+		{Key: "v", Desc: "compare values"},
+		{Key: "Esc/h", Desc: "back"},
+		{Key: "q", Desc: "close"},
+	}
+}
+
 // GenerateViewBindings returns the keybindings for the generate code view
 func GenerateViewBindings() []FooterBinding {
 	return []FooterBinding{
 		{Key: "↑↓/jk", Desc: "templates"},
 		{Key: "Enter", Desc: "generate"},
+		{Key: "Tab", Desc: "version"},
 		{Key: "Esc/h", Desc: "back"},
 		{Key: "^P", Desc: "project"},
 		{Key: "q", Desc: "quit"},
@@ -274,3 +434,18 @@ func LockedViewBindings() []FooterBinding {
 	}
 }
 
+// LoadingBindings returns the keybindings while a cancellable request runs
+func LoadingBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "Esc", Desc: "cancel"},
+	}
+}
+
+// RetryBindings returns the keybindings for the retry prompt
+func RetryBindings() []FooterBinding {
+	return []FooterBinding{
+		{Key: "r", Desc: "retry"},
+		{Key: "Esc", Desc: "give up"},
+	}
+}
+
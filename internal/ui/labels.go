@@ -0,0 +1,432 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// labelRow is one key/value pair in the label editor, which also edits
+// annotations when labelAnnotations is set
+type labelRow struct {
+	key   textinput.Model
+	value textinput.Model
+
+	// The value as loaded and as the input shows it. A single-line input
+	// turns newlines and tabs into spaces, so an untouched value is saved
+	// from original rather than from the input.
+	original string
+	shown    string
+}
+
+type labelsUpdatedMsg struct {
+	secretName  string
+	annotations bool // The update replaced the annotations rather than the labels
+	oldLabels   map[string]string
+	labels      map[string]string
+	secret      *gcp.Secret // Secret as stored after the update, with its new etag
+	err         error
+}
+
+type labelsReloadedMsg struct {
+	secret *gcp.Secret
+	err    error
+}
+
+func (m Model) newLabelRow(key, value string) labelRow {
+	k := textinput.New()
+	k.Placeholder = "key"
+	k.CharLimit = gcp.MaxLabelLength
+	k.Width = 24
+	k.SetValue(key)
+
+	v := textinput.New()
+	v.Placeholder = "value"
+	v.CharLimit = gcp.MaxLabelLength
+	v.Width = 24
+	if m.labelAnnotations {
+		// Annotation values are free-form, e.g. a runbook URL
+		v.CharLimit = gcp.MaxAnnotationsSize
+		v.Width = 48
+	}
+	v.SetValue(value)
+
+	return labelRow{key: k, value: v, original: value, shown: v.Value()}
+}
+
+// openLabels shows the label editor for the selected secret
+func (m Model) openLabels() (tea.Model, tea.Cmd) {
+	m.labelAnnotations = false
+	return m.openLabelEditor()
+}
+
+// openAnnotations shows the label editor on the annotations of the selected secret
+func (m Model) openAnnotations() (tea.Model, tea.Cmd) {
+	m.labelAnnotations = true
+	return m.openLabelEditor()
+}
+
+// editorKind names what the label editor edits, "labels" or "annotations"
+func (m Model) editorKind() string {
+	if m.labelAnnotations {
+		return "annotations"
+	}
+	return "labels"
+}
+
+// editorMetadata returns the labels or annotations of the selected secret
+func (m Model) editorMetadata() map[string]string {
+	if m.labelAnnotations {
+		return m.selectedSecret.Annotations
+	}
+	return m.selectedSecret.Labels
+}
+
+// setLabelRows fills the editor with the given pairs, sorted by key, and an empty row
+func (m Model) setLabelRows(pairs map[string]string) Model {
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m.labelRows = nil
+	for _, k := range keys {
+		m.labelRows = append(m.labelRows, m.newLabelRow(k, pairs[k]))
+	}
+	m.labelRows = append(m.labelRows, m.newLabelRow("", ""))
+	return m
+}
+
+func (m Model) openLabelEditor() (tea.Model, tea.Cmd) {
+	if m.selectedSecret == nil {
+		return m, nil
+	}
+
+	m = m.setLabelRows(m.editorMetadata())
+	m.labelFocus = 0
+	m.labelsConflict = false
+	m = m.focusLabel()
+	m.view = ViewLabels
+	return m, textinput.Blink
+}
+
+// focusLabel focuses the input at labelFocus (row*2 for keys, row*2+1 for values)
+func (m Model) focusLabel() Model {
+	for i := range m.labelRows {
+		m.labelRows[i].key.Blur()
+		m.labelRows[i].value.Blur()
+	}
+	if len(m.labelRows) == 0 {
+		return m
+	}
+	row := m.labelFocus / 2
+	if m.labelFocus%2 == 0 {
+		m.labelRows[row].key.Focus()
+	} else {
+		m.labelRows[row].value.Focus()
+	}
+	return m
+}
+
+func (m Model) updateLabels(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	// The secret changed while editing
+	if m.labelsConflict {
+		switch msg.String() {
+		case "r":
+			m.loading = true
+			m.loadingMsg = "Reloading secret..."
+			return m, m.reloadSecret(m.selectedSecret.ID())
+		case "n", "esc":
+			m.labelsConflict = false
+			m.labelRows = nil
+			m.view = ViewDetail
+		}
+		return m, nil
+	}
+
+	fields := len(m.labelRows) * 2
+	switch msg.String() {
+	case "tab", "down":
+		m.labelFocus = (m.labelFocus + 1) % fields
+		return m.focusLabel(), nil
+	case "shift+tab", "up":
+		m.labelFocus = (m.labelFocus - 1 + fields) % fields
+		return m.focusLabel(), nil
+	case "ctrl+n":
+		m.labelRows = append(m.labelRows, m.newLabelRow("", ""))
+		m.labelFocus = (len(m.labelRows) - 1) * 2
+		return m.focusLabel(), textinput.Blink
+	case "ctrl+d":
+		row := m.labelFocus / 2
+		m.labelRows = append(m.labelRows[:row], m.labelRows[row+1:]...)
+		if len(m.labelRows) == 0 {
+			m.labelRows = append(m.labelRows, m.newLabelRow("", ""))
+		}
+		if m.labelFocus >= len(m.labelRows)*2 {
+			m.labelFocus = len(m.labelRows)*2 - 2
+		}
+		return m.focusLabel(), nil
+	case "ctrl+s", "enter":
+		labels, err := m.editedLabels()
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		if maps.Equal(labels, m.editorMetadata()) {
+			m.view = ViewDetail
+			m.statusMsg = fmt.Sprintf("%s unchanged", titleCase(m.editorKind()))
+			m.statusErr = false
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = fmt.Sprintf("Updating %s...", m.editorKind())
+		return m, m.updateSecretLabels(m.selectedSecret.ID(), m.editorMetadata(), labels, m.selectedSecret.Etag)
+	case "esc":
+		m.labelRows = nil
+		m.view = ViewDetail
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	row := m.labelFocus / 2
+	if m.labelFocus%2 == 0 {
+		m.labelRows[row].key, cmd = m.labelRows[row].key.Update(msg)
+	} else {
+		m.labelRows[row].value, cmd = m.labelRows[row].value.Update(msg)
+	}
+	return m, cmd
+}
+
+// editedLabels collects the edited rows, ignoring empty ones, and validates
+// them against the Secret Manager label or annotation syntax
+func (m Model) editedLabels() (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, row := range m.labelRows {
+		key := strings.TrimSpace(row.key.Value())
+		// Label values are trimmed, annotation values are kept as typed
+		value := row.value.Value()
+		switch {
+		case value == row.shown:
+			value = row.original
+		case !m.labelAnnotations:
+			value = strings.TrimSpace(value)
+		}
+		if key == "" && strings.TrimSpace(value) == "" {
+			continue
+		}
+		if _, dup := labels[key]; dup {
+			return nil, fmt.Errorf("duplicate key %q", key)
+		}
+		labels[key] = value
+	}
+	validate := gcp.ValidateLabels
+	if m.labelAnnotations {
+		validate = gcp.ValidateAnnotations
+	}
+	if err := validate(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (m Model) updateSecretLabels(secretName string, oldLabels, labels map[string]string, etag string) tea.Cmd {
+	annotations := m.labelAnnotations
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		msg := labelsUpdatedMsg{secretName: secretName, annotations: annotations, oldLabels: oldLabels, labels: labels}
+		if annotations {
+			msg.err = m.client.UpdateSecretAnnotations(ctx, secretName, labels, etag)
+		} else {
+			msg.err = m.client.UpdateSecretLabels(ctx, secretName, labels, etag)
+		}
+		if msg.err == nil {
+			// Pick up the new etag; a failure here only means the next edit reloads first
+			msg.secret, _ = m.client.GetSecret(ctx, secretName)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) reloadSecret(secretName string) tea.Cmd {
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		secret, err := m.client.GetSecret(ctx, secretName)
+		return labelsReloadedMsg{secret: secret, err: err}, err
+	}})
+}
+
+func (m Model) handleLabelsUpdated(msg labelsUpdatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		log := m.auditLogger.LogLabelsUpdate
+		if msg.annotations {
+			log = m.auditLogger.LogAnnotationsUpdate
+		}
+		log(m.config.ProjectID, msg.secretName, gcp.FormatLabels(msg.oldLabels), gcp.FormatLabels(msg.labels), result, errMsg)
+	}
+	if msg.err != nil {
+		if gcp.IsConflict(msg.err) {
+			m.labelsConflict = true
+			m.statusMsg = fmt.Sprintf("%s were changed by someone else", titleCase(m.editorKind()))
+		} else {
+			m.statusMsg = fmt.Sprintf("Error updating %s: %v", m.editorKind(), msg.err)
+		}
+		m.statusErr = true
+		return m, nil
+	}
+	if m.selectedSecret != nil && m.selectedSecret.ID() == msg.secretName {
+		if msg.annotations {
+			m.selectedSecret.Annotations = msg.labels
+		} else {
+			m.selectedSecret.Labels = msg.labels
+		}
+		m.selectedSecret.Etag = ""
+		if msg.secret != nil {
+			m.selectedSecret.Etag = msg.secret.Etag
+		}
+	}
+	m.labelRows = nil
+	m.view = ViewDetail
+	m.statusMsg = fmt.Sprintf("✓ %s updated", titleCase(m.editorKind()))
+	m.statusErr = false
+	return m, nil
+}
+
+// handleLabelsReloaded reapplies the pending edit on top of the reloaded
+// labels or annotations and reopens the editor for review
+func (m Model) handleLabelsReloaded(msg labelsReloadedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Error reloading secret: %v", msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+
+	edited, _ := m.editedLabels()
+	current := msg.secret.Labels
+	if m.labelAnnotations {
+		current = msg.secret.Annotations
+	}
+	merged := reapplyLabels(m.editorMetadata(), edited, current)
+
+	// Keep the list in sync, selectedSecret points into m.secrets
+	m.selectedSecret.Labels = msg.secret.Labels
+	m.selectedSecret.Annotations = msg.secret.Annotations
+	m.selectedSecret.Etag = msg.secret.Etag
+
+	m = m.setLabelRows(merged)
+	m.labelFocus = 0
+	m.labelsConflict = false
+	m = m.focusLabel()
+	m.statusMsg = fmt.Sprintf("Reloaded, your changes were reapplied on the latest %s; review and save", m.editorKind())
+	m.statusErr = false
+	return m, textinput.Blink
+}
+
+// reapplyLabels applies the change from base to edited on top of current:
+// keys removed in the edit are removed, added or changed keys are set, and
+// keys the edit did not touch keep their current value
+func reapplyLabels(base, edited, current map[string]string) map[string]string {
+	out := make(map[string]string, len(current))
+	for k, v := range current {
+		out[k] = v
+	}
+	for k := range base {
+		if _, ok := edited[k]; !ok {
+			delete(out, k)
+		}
+	}
+	for k, v := range edited {
+		if old, ok := base[k]; !ok || old != v {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (m Model) viewLabels() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+	if m.labelsConflict {
+		return m.viewLabelsConflict()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("%s of %s", titleCase(m.editorKind()), m.selectedSecret.ID())))
+	b.WriteString("\n\n")
+
+	valueWidth := 28
+	if m.labelAnnotations {
+		valueWidth = 52
+	}
+	b.WriteString(m.styles.InputLabel.Render(fmt.Sprintf("%-28s %s", "Key", "Value")))
+	b.WriteString("\n")
+	for i, row := range m.labelRows {
+		keyStyle, valueStyle := m.styles.Input, m.styles.Input
+		if m.labelFocus == i*2 {
+			keyStyle = m.styles.InputFocused
+		}
+		if m.labelFocus == i*2+1 {
+			valueStyle = m.styles.InputFocused
+		}
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			keyStyle.Width(28).Render(row.key.View()), " ",
+			valueStyle.Width(valueWidth).Render(row.value.View())))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Point at the first problem while typing
+	if _, err := m.editedLabels(); err != nil {
+		b.WriteString(m.styles.StatusError.Render("✕ " + err.Error()))
+	} else if m.labelAnnotations {
+		b.WriteString(m.styles.SubtleText().Render("Keys: letters, digits, -, _ and . up to 63 characters; values are free-form"))
+	} else {
+		b.WriteString(m.styles.SubtleText().Render("Lowercase letters, digits, _ and -; keys start with a letter; up to 63 characters"))
+	}
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+func (m Model) viewLabelsConflict() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.StatusWarning.Bold(true).Render(fmt.Sprintf("⚠ %s Changed", titleCase(m.editorKind()))))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("'%s' was modified after you opened the editor.\n", m.selectedSecret.ID()))
+	b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("Nothing was saved. Reload to apply your changes on top of the latest %s.", m.editorKind())))
+	b.WriteString("\n\n")
+	b.WriteString("Press ")
+	b.WriteString(m.styles.FooterKey.Render("r"))
+	b.WriteString(" to reload and reapply or ")
+	b.WriteString(m.styles.FooterKey.Render("n"))
+	b.WriteString(" to discard your changes")
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+// titleCase upper-cases the first letter of an ASCII word
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// loadSecrets starts listing the project page by page, superseding any
+// listing still in progress. Global secrets come first, then the regional
+// secrets of each configured location.
+func (m *Model) loadSecrets() tea.Cmd {
+	m.listGen++
+	m.listing = true
+	m.listFailures = nil
+	return m.loadSecretsPage(m.listGen, 0, "")
+}
+
+func (m Model) loadSecretsPage(gen, loc int, pageToken string) tea.Cmd {
+	client := m.client
+	req := gcp.ListRequest{
+		Filter:    m.serverFilter,
+		PageSize:  gcp.DefaultPageSize,
+		PageToken: pageToken,
+		Location:  m.listLocations()[loc],
+	}
+	first := loc == 0 && pageToken == ""
+	// Only the first page is fetched behind the loading splash
+	op := operation{background: !first}
+	op.run = func(ctx context.Context) (tea.Msg, error) {
+		msg := secretsLoadedMsg{gen: gen, first: first, loc: loc}
+		page, err := client.ListSecretsPage(ctx, req)
+		if err != nil {
+			msg.err = err
+			return msg, err
+		}
+		msg.secrets = page.Secrets
+		msg.nextToken = page.NextPageToken
+		return msg, nil
+	}
+	return m.startOp(op)
+}
+
+// handleSecretsLoaded merges a page into the list and requests the next one.
+// The list is usable from the first page on; the cursor stays on its item
+// while later pages are merged in.
+func (m Model) handleSecretsLoaded(msg secretsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.gen != m.listGen {
+		return m, nil
+	}
+	if msg.first {
+		m.loading = false
+	}
+	if msg.err != nil && msg.loc > 0 {
+		// A location failing to list leaves the rest of the project usable
+		location := m.listLocations()[msg.loc]
+		m.listFailures = append(m.listFailures, location)
+		if m.auditLogger != nil {
+			m.auditLogger.LogSecretList(m.config.ProjectID, len(m.secrets), audit.ResultFailure,
+				fmt.Sprintf("location %s: %v", location, msg.err))
+		}
+		return m, m.nextSecretsPage(msg.gen, msg.loc, "")
+	}
+	if msg.err != nil {
+		loaded := 0
+		if !msg.first {
+			loaded = len(m.secrets)
+		}
+		m.listing = false
+		m.statusMsg = fmt.Sprintf("Error loading secrets: %v", msg.err)
+		m.statusErr = true
+		if m.auditLogger != nil {
+			m.auditLogger.LogSecretList(m.config.ProjectID, loaded, audit.ResultFailure, msg.err.Error())
+		}
+		return m, nil
+	}
+
+	var cursorPath string
+	if m.cursor < len(m.displayItems) {
+		cursorPath = m.displayItems[m.cursor].FullPath
+	}
+	offset := m.listOffset
+
+	if msg.first {
+		m.secrets = msg.secrets
+	} else {
+		m.secrets = append(m.secrets, msg.secrets...)
+	}
+	// Appending may move the backing array, selectedSecret must point into it
+	if m.selectedSecret != nil {
+		for i := range m.secrets {
+			if m.secrets[i].ID() == m.selectedSecret.ID() {
+				m.selectedSecret = &m.secrets[i]
+				break
+			}
+		}
+	}
+	m.buildFolderTree()
+	m.updateDisplayItems()
+	m.restoreCursor(cursorPath, offset)
+
+	return m, m.nextSecretsPage(msg.gen, msg.loc, msg.nextToken)
+}
+
+// nextSecretsPage requests the page after the one just merged: the next page
+// of the same location, else the first page of the next location. Once every
+// location is listed it completes the listing and returns nil.
+func (m *Model) nextSecretsPage(gen, loc int, nextToken string) tea.Cmd {
+	if nextToken != "" {
+		return m.loadSecretsPage(gen, loc, nextToken)
+	}
+	if loc+1 < len(m.listLocations()) {
+		return m.loadSecretsPage(gen, loc+1, "")
+	}
+
+	m.listing = false
+	m.statusMsg = fmt.Sprintf("Loaded %d secrets", len(m.secrets))
+	m.statusErr = false
+	if len(m.listFailures) > 0 {
+		m.statusMsg += fmt.Sprintf(", failed to list %s", strings.Join(m.listFailures, ", "))
+		m.statusErr = true
+		return nil
+	}
+	if m.auditLogger != nil {
+		m.auditLogger.LogSecretList(m.config.ProjectID, len(m.secrets), audit.ResultSuccess, "")
+	}
+	return nil
+}
+
+// restoreCursor moves the cursor back to the item at path after the display
+// items were rebuilt, keeping the scroll position when possible
+func (m *Model) restoreCursor(path string, offset int) {
+	for i, item := range m.displayItems {
+		if item.FullPath == path {
+			m.cursor = i
+			break
+		}
+	}
+
+	visibleHeight := m.height - 10
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+	m.listOffset = offset
+	if m.cursor < m.listOffset {
+		m.listOffset = m.cursor
+	}
+	if m.cursor >= m.listOffset+visibleHeight {
+		m.listOffset = m.cursor - visibleHeight + 1
+	}
+}
+
+// listComplete reports whether every page has been loaded, telling the user
+// to wait otherwise. Actions working on whole folders or projects need it.
+func (m *Model) listComplete() bool {
+	if !m.listing {
+		return true
+	}
+	m.statusMsg = fmt.Sprintf("Still loading secrets (%d so far), try again when done", len(m.secrets))
+	m.statusErr = true
+	return false
+}
+
+// openServerFilter shows the dialog for the Secret Manager list filter
+func (m Model) openServerFilter() (tea.Model, tea.Cmd) {
+	m.serverFilterInput = textinput.New()
+	m.serverFilterInput.Placeholder = "labels.env=prod AND name:db"
+	m.serverFilterInput.CharLimit = 500
+	m.serverFilterInput.Width = 50
+	m.serverFilterInput.SetValue(m.serverFilter)
+	m.serverFilterInput.Focus()
+	m.view = ViewServerFilter
+	return m, textinput.Blink
+}
+
+func (m Model) updateServerFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.serverFilter = strings.TrimSpace(m.serverFilterInput.Value())
+		m.currentPath = []string{}
+		m.selected = nil
+		m.cursor = 0
+		m.listOffset = 0
+		m.view = ViewList
+		m.loading = true
+		m.loadingMsg = "Loading secrets..."
+		cmd := m.loadSecrets()
+		return m, cmd
+	case "esc":
+		m.view = ViewList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.serverFilterInput, cmd = m.serverFilterInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) viewServerFilter() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render("Server-Side Filter"))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputFocused.Width(55).Render(m.serverFilterInput.View()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("Secret Manager filter syntax, applied when listing:"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("name:db • labels.env=prod • labels.team:* • NOT labels.deprecated:*"))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("Leave empty to list every secret"))
+
+	return m.styles.Dialog.Render(b.String())
+}
@@ -15,8 +15,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/bulk"
 	"github.com/theburrowhub/go-secret/internal/clipboard"
 	"github.com/theburrowhub/go-secret/internal/config"
+	"github.com/theburrowhub/go-secret/internal/filter"
 	"github.com/theburrowhub/go-secret/internal/gcp"
 )
 
@@ -43,6 +45,19 @@ const (
 	ViewReveal
 	ViewProjectSwitch
 	ViewLocked
+	ViewExport
+	ViewCopy
+	ViewDiff
+	ViewVersionAction
+	ViewLabels
+	ViewGroupBy
+	ViewServerFilter
+	ViewExpiry
+	ViewRotation
+	ViewAlias
+	ViewIAMGrant
+	ViewIAMConfirm
+	ViewPermissions
 )
 
 // FolderItem represents either a folder or a secret in the tree view
@@ -60,9 +75,10 @@ type Model struct {
 	// Config
 	config *config.Config
 	
-	// GCP client
-	client *gcp.Client
-	ctx    context.Context
+	// Secret store (GCP Secret Manager or any other backend)
+	client    gcp.SecretStore
+	openStore gcp.StoreOpener
+	ctx       context.Context
 	
 	// UI state
 	view           View
@@ -80,7 +96,17 @@ type Model struct {
 	cursor         int
 	listOffset     int
 	filterText     string
+	filterErr      string // Parse error of filterText, see package filter
 	filterInput    textinput.Model
+	groupBy        string // Label key the list is grouped by, empty for folders
+	groupKeys      []string
+	groupCursor    int
+	listing        bool   // Pages are still being loaded, see loadSecrets
+	listGen        int    // Current listing, pages of older ones are dropped
+	listFailures   []string // Locations whose regional secrets failed to list
+	serverFilter   string // Secret Manager filter applied when listing
+	serverFilterInput textinput.Model
+	selected       map[string]bool // Multi-selection, see selectionKey
 	
 	// Detail view state
 	selectedSecret *gcp.Secret
@@ -92,9 +118,13 @@ type Model struct {
 	// Create view state
 	createInputs       []textinput.Model
 	createFocus        int
-	createLocationIdx  int    // -1 = add new, 0 = global, 1+ = index+1 in config.SecretLocations
+	createLocationIdx  int    // Cursor: -1 = add new, 0 = global, 1+ = index+1 in config.SecretLocations
+	createRegions      map[string]bool // Replica locations toggled on, none = global
 	createAddingLoc    bool   // true when typing a new location
 	createLocInput     textinput.Model
+	createKMSInputs    map[string]textinput.Model // KMS key per selected location
+	createExpiryInput  textinput.Model
+	createRegional     string          // Location of a regional secret, empty for a global one
 	createValueArea    textarea.Model  // textarea for multiline secrets
 	createEditorMode   bool            // true = textarea, false = password input
 	
@@ -102,8 +132,9 @@ type Model struct {
 	versionInput   textinput.Model
 	
 	// Generate view state
-	templateCursor int
-	generatedCode  string
+	templateCursor  int
+	templateVersion string // Version or alias generated templates access
+	generatedCode   string
 	
 	// Config view state
 	configInputs      []textinput.Model
@@ -137,6 +168,66 @@ type Model struct {
 	// Delete confirmation
 	deleteConfirm  bool
 	
+	// Version state change confirmation
+	versionAction       versionAction
+	versionActionTarget string
+	destroyInput        textinput.Model
+	
+	// Label editor state
+	labelRows        []labelRow
+	labelFocus       int
+	labelsConflict   bool
+	labelAnnotations bool // The label editor edits annotations instead
+	
+	// Expiration dialog state
+	expiryInput textinput.Model
+	
+	// Rotation dialog state
+	rotationInputs []textinput.Model
+	rotationFocus  int
+	
+	// Alias dialog state
+	aliasInput  textinput.Model
+	aliasTarget string // Version the alias is assigned to
+	
+	// IAM tab state
+	detailTab  detailTab
+	iamSecret  string         // Secret the policy was loaded for
+	iamPolicy  *gcp.IAMPolicy // Nil until loaded
+	iamCursor  int
+	iamInput   textinput.Model
+	iamPending gcp.IAMPolicy // Policy set once iamChanges are confirmed
+	iamChanges []gcp.IAMChange
+	
+	// Permissions of the caller, nil until tested or when testing failed
+	projectPerms        gcp.Permissions
+	projectPermsErr     error
+	secretPerms         gcp.Permissions
+	secretPermsErr      error
+	secretPermsFor      string // Secret secretPerms were tested on
+	permissionsPrevView View
+	
+	// Export dialog state
+	exportFolder    string
+	exportFormatIdx int
+	exportPathInput textinput.Model
+	
+	// Copy dialog state
+	copySources   []gcp.Secret
+	copyTargets   []string
+	copyTargetIdx int
+	copySync      bool
+	copyTarget    gcp.SecretStore
+	copyPlan      *bulk.Plan
+	
+	// Diff view state
+	diffPrefix    string
+	diffTargets   []string
+	diffTargetIdx int
+	diffValues    bool
+	diffResult    *bulk.Diff
+	diffOffset    int
+	
 	// Status message
 	statusMsg      string
 	statusErr      bool
@@ -147,6 +238,9 @@ type Model struct {
 	// Loading state
 	loading        bool
 	loadingMsg     string
+	ops            *opTracker
+	retry          *opFailedMsg // Failed operation offered for retry
+	retryWaiting   bool
 	
 	// Clipboard auto-clear state
 	clipboardClearAt time.Time
@@ -163,8 +257,12 @@ type Model struct {
 
 // Messages
 type secretsLoadedMsg struct {
-	secrets []gcp.Secret
-	err     error
+	gen       int  // Listing the page belongs to
+	first     bool // First page, replaces the loaded secrets
+	loc       int  // Index into listLocations
+	secrets   []gcp.Secret
+	nextToken string
+	err       error
 }
 
 type versionsLoadedMsg struct {
@@ -201,7 +299,7 @@ type secretCopiedMsg struct {
 }
 
 type clientInitializedMsg struct {
-	client *gcp.Client
+	client gcp.SecretStore
 	err    error
 }
 
@@ -211,8 +309,9 @@ type clipboardTickMsg time.Time
 
 type sessionTimeoutMsg time.Time
 
-// NewModel creates a new application model
-func NewModel(cfg *config.Config, projectID string) Model {
+// NewModel creates a new application model.
+// openStore is used to connect to the secret store of the selected project.
+func NewModel(cfg *config.Config, projectID string, openStore gcp.StoreOpener) Model {
 	styles := NewStyles()
 	keys := DefaultKeyMap()
 	
@@ -265,7 +364,7 @@ func NewModel(cfg *config.Config, projectID string) Model {
 	templateTitleInput.CharLimit = 100
 	
 	templateCodeArea := textarea.New()
-	templateCodeArea.Placeholder = "Template code...\nUse {{.SecretName}}, {{.FullSecretName}}, {{.ProjectID}}"
+	templateCodeArea.Placeholder = "Template code...\nUse {{.SecretName}}, {{.FullSecretName}}, {{.Location}}, {{.ResourceName}}, {{.ProjectID}}, {{.Version}}, {{.Annotations.key}}"
 	templateCodeArea.CharLimit = 4096
 	templateCodeArea.SetWidth(60)
 	templateCodeArea.SetHeight(8)
@@ -304,7 +403,9 @@ func NewModel(cfg *config.Config, projectID string) Model {
 	
 	return Model{
 		config:             cfg,
+		openStore:          openStore,
 		ctx:                context.Background(),
+		ops:                &opTracker{},
 		view:               initialView,
 		styles:             styles,
 		keys:               keys,
@@ -344,7 +445,7 @@ func (m Model) Init() tea.Cmd {
 
 func (m Model) initializeClient() tea.Cmd {
 	return func() tea.Msg {
-		client, err := gcp.NewClient(m.ctx, m.config.ProjectID)
+		client, err := m.openStore(m.ctx, m.config.ProjectID)
 		if err != nil {
 			return clientInitializedMsg{err: err}
 		}
@@ -352,64 +453,58 @@ func (m Model) initializeClient() tea.Cmd {
 	}
 }
 
-func (m Model) loadSecrets() tea.Cmd {
-	return func() tea.Msg {
-		secrets, err := m.client.ListSecrets(m.ctx)
-		return secretsLoadedMsg{secrets: secrets, err: err}
-	}
-}
-
 func (m Model) loadVersions(secretName string) tea.Cmd {
-	return func() tea.Msg {
-		versions, err := m.client.ListSecretVersions(m.ctx, secretName)
-		return versionsLoadedMsg{versions: versions, err: err}
-	}
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		versions, err := m.client.ListSecretVersions(ctx, secretName)
+		return versionsLoadedMsg{versions: versions, err: err}, err
+	}})
 }
 
 func (m Model) accessSecretVersion(secretName, version string) tea.Cmd {
-	return func() tea.Msg {
-		value, err := m.client.AccessSecretVersion(m.ctx, secretName, version)
-		return secretValueMsg{secretName: secretName, value: value, version: version, err: err}
-	}
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		value, err := m.client.AccessSecretVersion(ctx, secretName, version)
+		return secretValueMsg{secretName: secretName, value: value, version: version, err: err}, err
+	}})
 }
 
-func (m Model) createSecret(name string, value []byte, location string) tea.Cmd {
-	return func() tea.Msg {
-		err := m.client.CreateSecret(m.ctx, name, nil, location)
+func (m Model) createSecret(spec gcp.Secret, value []byte) tea.Cmd {
+	name := spec.ID()
+	return m.startOp(operation{mutates: true, run: func(ctx context.Context) (tea.Msg, error) {
+		err := m.client.CreateSecret(ctx, spec)
 		if err != nil {
-			return secretCreatedMsg{name: name, err: err}
+			return secretCreatedMsg{name: name, err: err}, err
 		}
 		
 		if len(value) > 0 {
-			_, err = m.client.AddSecretVersion(m.ctx, name, value)
+			_, err = m.client.AddSecretVersion(ctx, name, value)
 		}
-		return secretCreatedMsg{name: name, err: err}
-	}
+		return secretCreatedMsg{name: name, err: err}, err
+	}})
 }
 
 func (m Model) deleteSecret(name string) tea.Cmd {
-	return func() tea.Msg {
-		err := m.client.DeleteSecret(m.ctx, name)
-		return secretDeletedMsg{name: name, err: err}
-	}
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		err := m.client.DeleteSecret(ctx, name)
+		return secretDeletedMsg{name: name, err: err}, err
+	}})
 }
 
 func (m Model) addVersion(secretName string, value []byte) tea.Cmd {
-	return func() tea.Msg {
-		version, err := m.client.AddSecretVersion(m.ctx, secretName, value)
-		return versionAddedMsg{version: version, err: err}
-	}
+	return m.startOp(operation{mutates: true, run: func(ctx context.Context) (tea.Msg, error) {
+		version, err := m.client.AddSecretVersion(ctx, secretName, value)
+		return versionAddedMsg{version: version, err: err}, err
+	}})
 }
 
 func (m Model) copySecretValue(secretName, version string) tea.Cmd {
-	return func() tea.Msg {
-		value, err := m.client.AccessSecretVersion(m.ctx, secretName, version)
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		value, err := m.client.AccessSecretVersion(ctx, secretName, version)
 		if err != nil {
-			return secretCopiedMsg{secretName: secretName, version: version, err: err}
+			return secretCopiedMsg{secretName: secretName, version: version, err: err}, err
 		}
 		err = clipboard.WriteText(string(value))
-		return secretCopiedMsg{secretName: secretName, version: version, err: err}
-	}
+		return secretCopiedMsg{secretName: secretName, version: version, err: err}, nil
+	}})
 }
 
 // clipboardTickCmd returns a command that ticks every second for countdown
@@ -485,6 +580,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateLocked(msg)
 		}
 		
+		// A failed operation waits for retry or give up
+		if m.retry != nil {
+			return m.updateRetry(msg)
+		}
+		
+		// Esc cancels the operation behind the loading splash
+		if m.loading && msg.String() == "esc" && m.ops.cancel != nil {
+			return m.cancelOp()
+		}
+		
 		// Global project switch (Ctrl+P) - available from most views
 		if msg.String() == "ctrl+p" && m.view != ViewProjectPrompt && m.view != ViewProjectSwitch && m.view != ViewLocked {
 			m.projectSwitchPrevView = m.view
@@ -533,6 +638,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateProjectSwitch(msg)
 		case ViewLocked:
 			return m.updateLocked(msg)
+		case ViewExport:
+			return m.updateExport(msg)
+		case ViewCopy:
+			return m.updateCopy(msg)
+		case ViewDiff:
+			return m.updateDiff(msg)
+		case ViewVersionAction:
+			return m.updateVersionAction(msg)
+		case ViewLabels:
+			return m.updateLabels(msg)
+		case ViewGroupBy:
+			return m.updateGroupBy(msg)
+		case ViewServerFilter:
+			return m.updateServerFilter(msg)
+		case ViewExpiry:
+			return m.updateExpiry(msg)
+		case ViewRotation:
+			return m.updateRotation(msg)
+		case ViewAlias:
+			return m.updateAlias(msg)
+		case ViewIAMGrant:
+			return m.updateIAMGrant(msg)
+		case ViewIAMConfirm:
+			return m.updateIAMConfirm(msg)
+		case ViewPermissions:
+			return m.updatePermissions(msg)
 		}
 		
 	case tea.WindowSizeMsg:
@@ -547,34 +678,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loading = false
 			return m, nil
 		}
+		// Release the connection to the previous project, if any
+		if m.client != nil {
+			_ = m.client.Close()
+		}
 		m.client = msg.client
+		m.selected = nil
+		m.groupBy = ""
+		m.serverFilter = ""
+		m.projectPerms, m.projectPermsErr = nil, nil
+		m.secretPerms, m.secretPermsErr, m.secretPermsFor = nil, nil, ""
 		if m.auditLogger != nil {
 			// Set the authenticated user in audit logger
 			m.auditLogger.SetUser(msg.client.UserEmail())
+			m.auditLogger.SetPrincipal(msg.client.Principal())
 			m.auditLogger.LogSessionStart(m.config.ProjectID)
 		}
 		m.loading = true
 		m.loadingMsg = "Loading secrets..."
-		return m, m.loadSecrets()
+		cmd := m.loadSecrets()
+		return m, tea.Batch(cmd, m.testPermissions(""))
+		
+	case opDoneMsg:
+		m.endOp(msg.id)
+		return m.Update(msg.msg)
+		
+	case opFailedMsg:
+		return m.handleOpFailed(msg)
+		
+	case opRetryMsg:
+		return m.handleOpRetry(msg)
 		
 	case secretsLoadedMsg:
-		m.loading = false
-		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Error loading secrets: %v", msg.err)
-			m.statusErr = true
-			if m.auditLogger != nil {
-				m.auditLogger.LogSecretList(m.config.ProjectID, 0, audit.ResultFailure, msg.err.Error())
-			}
-			return m, nil
-		}
-		m.secrets = msg.secrets
-		m.buildFolderTree()
-		m.updateDisplayItems()
-		m.statusMsg = fmt.Sprintf("Loaded %d secrets", len(m.secrets))
-		m.statusErr = false
-		if m.auditLogger != nil {
-			m.auditLogger.LogSecretList(m.config.ProjectID, len(m.secrets), audit.ResultSuccess, "")
-		}
+		return m.handleSecretsLoaded(msg)
 		
 	case versionsLoadedMsg:
 		m.loading = false
@@ -601,7 +737,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.auditLogger.LogSecretCreate(m.config.ProjectID, msg.name, audit.ResultSuccess, "")
 		}
 		m.view = ViewList
-		return m, m.loadSecrets()
+		cmd := m.loadSecrets()
+		return m, cmd
 		
 	case secretDeletedMsg:
 		m.loading = false
@@ -620,7 +757,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.view = ViewList
 		m.selectedSecret = nil
-		return m, m.loadSecrets()
+		cmd := m.loadSecrets()
+		return m, cmd
 		
 	case versionAddedMsg:
 		m.loading = false
@@ -628,17 +766,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Error adding version: %v", msg.err)
 			m.statusErr = true
 			if m.auditLogger != nil && m.selectedSecret != nil {
-				m.auditLogger.LogVersionAdd(m.config.ProjectID, m.selectedSecret.Name, "", audit.ResultFailure, msg.err.Error())
+				m.auditLogger.LogVersionAdd(m.config.ProjectID, m.selectedSecret.ID(), "", audit.ResultFailure, msg.err.Error())
 			}
 			return m, nil
 		}
 		m.statusMsg = fmt.Sprintf("Version %s added successfully", msg.version.Name)
 		m.statusErr = false
 		if m.auditLogger != nil && m.selectedSecret != nil {
-			m.auditLogger.LogVersionAdd(m.config.ProjectID, m.selectedSecret.Name, msg.version.Name, audit.ResultSuccess, "")
+			m.auditLogger.LogVersionAdd(m.config.ProjectID, m.selectedSecret.ID(), msg.version.Name, audit.ResultSuccess, "")
 		}
 		m.view = ViewDetail
-		return m, m.loadVersions(m.selectedSecret.Name)
+		return m, m.loadVersions(m.selectedSecret.ID())
 		
 	case secretValueMsg:
 		m.loading = false
@@ -688,6 +826,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.statusMsg = "✓ Secret value copied to clipboard"
 		m.statusErr = false
 		
+	case exportDoneMsg:
+		return m.handleExportDone(msg)
+		
+	case copyPlannedMsg:
+		return m.handleCopyPlanned(msg)
+		
+	case copyDoneMsg:
+		return m.handleCopyDone(msg)
+		
+	case diffDoneMsg:
+		return m.handleDiffDone(msg)
+		
+	case versionStateChangedMsg:
+		return m.handleVersionStateChanged(msg)
+		
+	case labelsUpdatedMsg:
+		return m.handleLabelsUpdated(msg)
+		
+	case labelsReloadedMsg:
+		return m.handleLabelsReloaded(msg)
+		
+	case expiryUpdatedMsg:
+		return m.handleExpiryUpdated(msg)
+		
+	case rotationUpdatedMsg:
+		return m.handleRotationUpdated(msg)
+		
+	case aliasUpdatedMsg:
+		return m.handleAliasUpdated(msg)
+		
+	case iamPolicyLoadedMsg:
+		return m.handleIAMPolicyLoaded(msg)
+		
+	case iamUpdatedMsg:
+		return m.handleIAMUpdated(msg)
+		
+	case permissionsTestedMsg:
+		return m.handlePermissionsTested(msg)
+		
 	case clipboardTickMsg:
 		if !m.clipboardActive {
 			return m, nil
@@ -771,6 +948,10 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		visibleHeight = 5
 	}
 	
+	if !m.checkPermission(listPermissions, msg.String()) {
+		return m, nil
+	}
+	
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -811,10 +992,11 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if item.Secret != nil {
 				m.selectedSecret = item.Secret
 				m.view = ViewDetail
+				m.detailTab = detailOverview
 				m.versionCursor = 0
 				m.loading = true
 				m.loadingMsg = "Loading versions..."
-				return m, m.loadVersions(item.Secret.Name)
+				return m, tea.Batch(m.loadVersions(item.Secret.ID()), m.testPermissions(item.Secret.ID()))
 			}
 		}
 	case "backspace", "h", "esc":
@@ -842,11 +1024,8 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.createInputs[0].Focus()
 		m.createFocus = 0
 		// Initialize location selector: default to first saved location if any, else global
-		if len(m.config.SecretLocations) > 0 {
-			m.createLocationIdx = 1 // First saved location
-		} else {
-			m.createLocationIdx = 0 // Global
-		}
+		m.resetCreateLocations()
+		m.createExpiryInput = newExpiryInput()
 		m.createAddingLoc = false
 		m.createEditorMode = false
 		m.createValueArea.SetValue("")
@@ -857,10 +1036,27 @@ func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.view = ViewDelete
 			m.deleteConfirm = false
 		}
+	case "e":
+		if len(m.displayItems) > 0 && m.displayItems[m.cursor].IsFolder {
+			return m.openExport(m.displayItems[m.cursor].FullPath)
+		}
+	case " ":
+		m = m.toggleSelection()
+	case "C":
+		return m.openCopy()
+	case "D":
+		return m.openDiff()
+	case "b":
+		return m.openGroupBy()
+	case "F":
+		return m.openServerFilter()
+	case "P":
+		return m.openPermissions()
 	case "ctrl+r":
 		m.loading = true
 		m.loadingMsg = "Refreshing..."
-		return m, m.loadSecrets()
+		cmd := m.loadSecrets()
+		return m, cmd
 	case "ctrl+s":
 		m.view = ViewConfigMenu
 		m.configMenuCursor = 0
@@ -877,6 +1073,16 @@ func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	
+	if msg.String() == "tab" {
+		return m.toggleDetailTab()
+	}
+	if m.detailTab == detailIAM {
+		return m.updateIAMTab(msg)
+	}
+	if !m.checkPermission(detailPermissions, msg.String()) {
+		return m, nil
+	}
+	
 	switch msg.String() {
 	case "up", "k":
 		if m.versionCursor > 0 {
@@ -900,14 +1106,14 @@ func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			version := m.versions[m.versionCursor]
 			m.loading = true
 			m.loadingMsg = "Accessing secret..."
-			return m, m.accessSecretVersion(m.selectedSecret.Name, version.Name)
+			return m, m.accessSecretVersion(m.selectedSecret.ID(), version.Name)
 		}
 	case "c", "y":
 		if len(m.versions) > 0 {
 			version := m.versions[m.versionCursor]
 			m.loading = true
 			m.loadingMsg = "Copying to clipboard..."
-			return m, m.copySecretValue(m.selectedSecret.Name, version.Name)
+			return m, m.copySecretValue(m.selectedSecret.ID(), version.Name)
 		}
 	case "a":
 		m.view = ViewAddVersion
@@ -917,10 +1123,29 @@ func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "g":
 		m.view = ViewGenerate
 		m.templateCursor = 0
+		m.templateVersion = m.defaultTemplateVersion()
 		m.generatedCode = ""
 	case "d":
 		m.view = ViewDelete
 		m.deleteConfirm = false
+	case "x":
+		return m.openVersionAction(versionDisable)
+	case "e":
+		return m.openVersionAction(versionEnable)
+	case "X":
+		return m.openVersionAction(versionDestroy)
+	case "L":
+		return m.openLabels()
+	case "N":
+		return m.openAnnotations()
+	case "t":
+		return m.openExpiry()
+	case "R":
+		return m.openRotation()
+	case "A":
+		return m.openAlias()
+	case "P":
+		return m.openPermissions()
 	case "q":
 		return m, tea.Quit
 	}
@@ -939,6 +1164,9 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				_ = m.config.Save()
 				// Point to the new location (index = len + 1 because 0 is global)
 				m.createLocationIdx = len(m.config.SecretLocations)
+				m.createRegions[newLoc] = true
+				m.createRegional = ""
+				m.syncCreateKMS()
 			}
 			m.createAddingLoc = false
 			m.createLocInput.SetValue("")
@@ -977,7 +1205,7 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Field navigation: 0=name, 1=value, 2=location
+	// Field navigation: 0=name, 1=value, 2=location, 3..=KMS keys, then expiry and regional location
 	switch msg.String() {
 	case "tab":
 		// Blur current field
@@ -989,9 +1217,11 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.createInputs[1].Blur()
 			}
+		} else if m.createFocus >= 3 {
+			m.blurCreateExtra()
 		}
 		// Move to next field
-		m.createFocus = (m.createFocus + 1) % 3
+		m.createFocus = (m.createFocus + 1) % m.createFields()
 		// Focus new field
 		if m.createFocus == 0 {
 			m.createInputs[0].Focus()
@@ -1003,6 +1233,9 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.createInputs[1].Focus()
 			return m, textinput.Blink
+		} else if m.createFocus >= 3 {
+			cmd := m.focusCreateExtra()
+			return m, cmd
 		}
 		return m, nil
 	case "shift+tab":
@@ -1015,11 +1248,13 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.createInputs[1].Blur()
 			}
+		} else if m.createFocus >= 3 {
+			m.blurCreateExtra()
 		}
 		// Move to previous field
 		m.createFocus--
 		if m.createFocus < 0 {
-			m.createFocus = 2
+			m.createFocus = m.createFields() - 1
 		}
 		// Focus new field
 		if m.createFocus == 0 {
@@ -1032,6 +1267,9 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.createInputs[1].Focus()
 			return m, textinput.Blink
+		} else if m.createFocus >= 3 {
+			cmd := m.focusCreateExtra()
+			return m, cmd
 		}
 		return m, nil
 	case "enter":
@@ -1061,12 +1299,19 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusErr = true
 			return m, nil
 		}
-		// Get selected location: 0=global (empty), 1+=config.SecretLocations[idx-1]
-		var location string
-		if m.createLocationIdx > 0 && m.createLocationIdx <= len(m.config.SecretLocations) {
-			location = m.config.SecretLocations[m.createLocationIdx-1]
+		expireTime, err := m.createExpireTime()
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		// Regional secret, or global one replicated to the selected locations
+		spec, err := m.createSpec(name, expireTime)
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
 		}
-		// location stays empty for global (index 0)
 		m.loading = true
 		m.loadingMsg = "Creating secret..."
 		// Clear inputs
@@ -1075,7 +1320,7 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.createValueArea.SetValue("")
 		m.createLocationIdx = 0
 		m.createEditorMode = false
-		return m, m.createSecret(name, []byte(value), location)
+		return m, m.createSecret(spec, []byte(value))
 	case "ctrl+s":
 		// Alternative submit shortcut (useful in editor mode)
 		name := m.createInputs[0].Value()
@@ -1090,9 +1335,17 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusErr = true
 			return m, nil
 		}
-		var location string
-		if m.createLocationIdx > 0 && m.createLocationIdx <= len(m.config.SecretLocations) {
-			location = m.config.SecretLocations[m.createLocationIdx-1]
+		expireTime, err := m.createExpireTime()
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		spec, err := m.createSpec(name, expireTime)
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
 		}
 		m.loading = true
 		m.loadingMsg = "Creating secret..."
@@ -1101,7 +1354,7 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.createValueArea.SetValue("")
 		m.createLocationIdx = 0
 		m.createEditorMode = false
-		return m, m.createSecret(name, []byte(value), location)
+		return m, m.createSecret(spec, []byte(value))
 	case "esc":
 		m.view = ViewList
 		m.createInputs[0].SetValue("")
@@ -1121,6 +1374,10 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.createFocus == m.createRegionalField() {
+			m.moveCreateRegional(-1)
+			return m, nil
+		}
 	case "right", "l":
 		// Navigate location options (only when on location field)
 		if m.createFocus == 2 {
@@ -1131,6 +1388,16 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.createFocus == m.createRegionalField() {
+			m.moveCreateRegional(1)
+			return m, nil
+		}
+	case " ":
+		// Toggle the region under the cursor (only when on location field)
+		if m.createFocus == 2 {
+			m.toggleCreateLocation()
+			return m, nil
+		}
 	}
 	
 	// Update focused field
@@ -1147,6 +1414,8 @@ func (m Model) updateCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.createInputs[1], cmd = m.createInputs[1].Update(msg)
 		return m, cmd
+	} else if m.createFocus >= 3 {
+		return m.updateCreateExtra(msg)
 	}
 	return m, nil
 }
@@ -1163,7 +1432,7 @@ func (m Model) updateAddVersion(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.loading = true
 		m.loadingMsg = "Adding version..."
 		m.versionInput.SetValue("")
-		return m, m.addVersion(m.selectedSecret.Name, []byte(value))
+		return m, m.addVersion(m.selectedSecret.ID(), []byte(value))
 	case "esc":
 		m.view = ViewDetail
 		m.versionInput.SetValue("")
@@ -1180,7 +1449,7 @@ func (m Model) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "y":
 		m.loading = true
 		m.loadingMsg = "Deleting secret..."
-		return m, m.deleteSecret(m.selectedSecret.Name)
+		return m, m.deleteSecret(m.selectedSecret.ID())
 	case "n", "esc":
 		if m.previousView == ViewDetail {
 			m.view = ViewDetail
@@ -1204,6 +1473,11 @@ func (m Model) updateGenerate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter":
 		m.generatedCode = m.generateCode(m.templateCursor)
+	case "tab":
+		m.nextTemplateVersion()
+		if m.generatedCode != "" {
+			m.generatedCode = m.generateCode(m.templateCursor)
+		}
 	case "esc", "backspace":
 		m.view = ViewDetail
 		m.generatedCode = ""
@@ -1774,6 +2048,11 @@ func (m Model) updateReveal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // buildFolderTree builds the folder tree from secrets
 func (m *Model) buildFolderTree() {
+	if m.groupBy != "" {
+		m.buildLabelTree()
+		return
+	}
+	
 	m.folderTree = &FolderItem{
 		Name:     "",
 		Children: make(map[string]*FolderItem),
@@ -1789,9 +2068,14 @@ func (m *Model) buildFolderTree() {
 				continue
 			}
 			
-			if _, exists := current.Children[part]; !exists {
-				isFolder := j < len(parts)-1
-				current.Children[part] = &FolderItem{
+			isFolder := j < len(parts)-1
+			// A regional secret may share its name with a global one
+			key := part
+			if !isFolder {
+				key = gcp.SecretID(part, secret.Location)
+			}
+			if _, exists := current.Children[key]; !exists {
+				current.Children[key] = &FolderItem{
 					Name:     part,
 					FullPath: strings.Join(parts[:j+1], m.config.FolderSeparator),
 					IsFolder: isFolder,
@@ -1799,10 +2083,11 @@ func (m *Model) buildFolderTree() {
 					Depth:    j,
 				}
 				if !isFolder {
-					current.Children[part].Secret = secret
+					current.Children[key].FullPath = secret.ID()
+					current.Children[key].Secret = secret
 				}
 			}
-			current = current.Children[part]
+			current = current.Children[key]
 		}
 	}
 }
@@ -1821,14 +2106,19 @@ func (m *Model) updateDisplayItems() {
 		}
 	}
 	
+	// An invalid query filters nothing until it is fixed
+	query, err := filter.Parse(m.filterText)
+	m.filterErr = ""
+	if err != nil {
+		m.filterErr = err.Error()
+	}
+	
 	// Collect items
 	items := make([]*FolderItem, 0, len(current.Children))
 	for _, item := range current.Children {
 		// Apply filter
-		if m.filterText != "" {
-			if !strings.Contains(strings.ToLower(item.Name), strings.ToLower(m.filterText)) {
-				continue
-			}
+		if !itemMatches(item, query) {
+			continue
 		}
 		items = append(items, item)
 	}
@@ -1838,7 +2128,11 @@ func (m *Model) updateDisplayItems() {
 		if items[i].IsFolder != items[j].IsFolder {
 			return items[i].IsFolder
 		}
-		return items[i].Name < items[j].Name
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		// Same name in several locations, global first
+		return items[i].FullPath < items[j].FullPath
 	})
 	
 	m.displayItems = items
@@ -1867,13 +2161,28 @@ func (m Model) generateCode(templateIdx int) string {
 	parts := strings.Split(m.selectedSecret.Name, m.config.FolderSeparator)
 	shortName := parts[len(parts)-1]
 	
-	data := map[string]string{
+	// Regional secrets live under their location
+	resourceName := fmt.Sprintf("projects/%s/secrets/%s", m.config.ProjectID, m.selectedSecret.Name)
+	if m.selectedSecret.Location != "" {
+		resourceName = fmt.Sprintf("projects/%s/locations/%s/secrets/%s", m.config.ProjectID, m.selectedSecret.Location, m.selectedSecret.Name)
+	}
+
+	annotations := m.selectedSecret.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	data := map[string]interface{}{
 		"SecretName":     shortName,
 		"FullSecretName": m.selectedSecret.Name,
+		"Location":       m.selectedSecret.Location,
+		"ResourceName":   resourceName,
 		"ProjectID":      m.config.ProjectID,
+		"Version":        m.templateVersion,
+		"Annotations":    annotations,
 	}
 	
-	t, err := template.New("code").Parse(tpl.Code)
+	// Missing annotations render empty rather than as <no value>
+	t, err := template.New("code").Option("missingkey=zero").Parse(tpl.Code)
 	if err != nil {
 		return fmt.Sprintf("Template error: %v", err)
 	}
@@ -1901,10 +2210,13 @@ func (m Model) View() string {
 		footer = InputViewBindings()
 	case ViewList:
 		content = m.viewList()
-		footer = ListViewBindings()
+		footer = m.greyOut(ListViewBindings(), listPermissions)
 	case ViewDetail:
 		content = m.viewDetail()
-		footer = DetailViewBindings()
+		footer = m.greyOut(DetailViewBindings(), detailPermissions)
+		if m.detailTab == detailIAM {
+			footer = m.greyOut(IAMTabBindings(), iamPermissions)
+		}
 	case ViewCreate:
 		content = m.viewCreate()
 		footer = CreateViewBindings()
@@ -1950,6 +2262,64 @@ func (m Model) View() string {
 	case ViewLocked:
 		content = m.viewLocked()
 		footer = LockedViewBindings()
+	case ViewExport:
+		content = m.viewExport()
+		footer = ExportViewBindings()
+	case ViewCopy:
+		content = m.viewCopy()
+		footer = CopyViewBindings()
+		if m.copyPlan != nil {
+			footer = ConfirmViewBindings()
+		}
+	case ViewDiff:
+		content = m.viewDiff()
+		footer = DiffViewBindings()
+		if m.diffResult != nil {
+			footer = DiffResultBindings()
+		}
+	case ViewVersionAction:
+		content = m.viewVersionAction()
+		footer = ConfirmViewBindings()
+		if m.versionAction == versionDestroy {
+			footer = DestroyVersionBindings()
+		}
+	case ViewLabels:
+		content = m.viewLabels()
+		footer = LabelsViewBindings()
+		if m.labelsConflict {
+			footer = LabelsConflictBindings()
+		}
+	case ViewGroupBy:
+		content = m.viewGroupBy()
+		footer = GroupByBindings()
+	case ViewServerFilter:
+		content = m.viewServerFilter()
+		footer = ServerFilterBindings()
+	case ViewExpiry:
+		content = m.viewExpiry()
+		footer = ExpiryViewBindings()
+	case ViewRotation:
+		content = m.viewRotation()
+		footer = RotationViewBindings()
+	case ViewAlias:
+		content = m.viewAlias()
+		footer = AliasViewBindings()
+	case ViewIAMGrant:
+		content = m.viewIAMGrant()
+		footer = IAMGrantBindings()
+	case ViewIAMConfirm:
+		content = m.viewIAMConfirm()
+		footer = ConfirmViewBindings()
+	case ViewPermissions:
+		content = m.viewPermissions()
+		footer = PermissionsViewBindings()
+	}
+	
+	if m.retry != nil && m.view != ViewLocked {
+		content = m.viewRetry()
+		footer = RetryBindings()
+	} else if m.loading && m.ops.cancel != nil {
+		footer = LoadingBindings()
 	}
 	
 	return m.renderLayout(content, footer)
@@ -1957,15 +2327,22 @@ func (m Model) View() string {
 
 func (m Model) renderLayout(content string, footerBindings []FooterBinding) string {
 	// Header
-	header := m.styles.Header.Width(m.width).Render(
-		fmt.Sprintf("🔐 GCP Secret Manager  │  %s", m.config.ProjectID),
-	)
+	title := fmt.Sprintf("🔐 GCP Secret Manager  │  %s", m.config.ProjectID)
+	if m.client != nil && m.client.Principal() != m.client.UserEmail() {
+		// Make it obvious when acting as a break-glass service account
+		title += fmt.Sprintf("  │  as %s", m.client.Principal())
+	}
+	header := m.styles.Header.Width(m.width).Render(title)
 	
 	// Footer with keybindings
 	var footerParts []string
 	for _, b := range footerBindings {
 		key := m.styles.FooterKey.Render(b.Key)
 		desc := m.styles.FooterDesc.Render(b.Desc)
+		if b.Disabled {
+			key = m.styles.FooterKey.Foreground(ColorTextMuted).Render(b.Key)
+			desc = m.styles.FooterDesc.Foreground(ColorTextMuted).Strikethrough(true).Render(b.Desc)
+		}
 		footerParts = append(footerParts, key+desc)
 	}
 	footerContent := lipgloss.JoinHorizontal(lipgloss.Left, footerParts...)
@@ -2037,9 +2414,30 @@ func (m Model) viewList() string {
 	b.WriteString(breadcrumb)
 	b.WriteString("\n\n")
 	
+	if m.serverFilter != "" {
+		b.WriteString(m.styles.StatusInfo.Render("Server filter: " + m.serverFilter))
+		b.WriteString("\n")
+	}
+	if m.listing {
+		b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("⏳ Loading… %d secrets so far", len(m.secrets))))
+		b.WriteString("\n")
+	}
+	if m.serverFilter != "" || m.listing {
+		b.WriteString("\n")
+	}
+	
 	// Filter indicator
 	if m.filterText != "" {
 		b.WriteString(m.styles.StatusWarning.Render(fmt.Sprintf("Filter: %s", m.filterText)))
+		if m.filterErr != "" {
+			b.WriteString(m.styles.StatusError.Render("  ✕ " + m.filterErr))
+		}
+		b.WriteString("\n\n")
+	}
+	
+	// Selection indicator
+	if len(m.selected) > 0 {
+		b.WriteString(m.styles.StatusInfo.Render(fmt.Sprintf("%d selected • C to copy to another project", len(m.selected))))
 		b.WriteString("\n\n")
 	}
 	
@@ -2065,6 +2463,8 @@ func (m Model) viewList() string {
 			b.WriteString("\n")
 		}
 		
+		layout := m.listColumns(startIdx, endIdx)
+		
 		// Render visible items
 		for i := startIdx; i < endIdx; i++ {
 			item := m.displayItems[i]
@@ -2078,6 +2478,16 @@ func (m Model) viewList() string {
 			
 			name := nameStyle.Render(item.Name)
 			line = fmt.Sprintf("%s %s", icon, name)
+			if layout.columns() && !item.IsFolder {
+				line = fmt.Sprintf("%s %s", icon, m.secretColumns(item, nameStyle, layout))
+			}
+			if len(m.selected) > 0 {
+				mark := "  "
+				if m.selected[m.selectionKey(item)] {
+					mark = "✓ "
+				}
+				line = mark + line
+			}
 			
 			if i == m.cursor {
 				line = m.styles.ListSelected.Width(m.width - 6).Render(line)
@@ -2120,15 +2530,71 @@ func (m Model) viewDetail() string {
 		fmt.Sprintf("🔐 %s", m.selectedSecret.Name),
 	))
 	b.WriteString("\n\n")
+	b.WriteString(m.detailTabs())
+	b.WriteString("\n\n")
+	
+	if m.detailTab == detailIAM {
+		b.WriteString(m.viewIAMTab())
+		return b.String()
+	}
 	
 	// Details
 	b.WriteString(m.styles.DetailLabel.Render("Created:"))
 	b.WriteString(m.styles.DetailValue.Render(m.selectedSecret.CreateTime))
 	b.WriteString("\n")
 	
+	b.WriteString(m.styles.DetailLabel.Render("Location:"))
+	b.WriteString(m.styles.DetailValue.Render(locationName(m.selectedSecret.Location)))
+	b.WriteString("\n")
+	
 	b.WriteString(m.styles.DetailLabel.Render("Replication:"))
-	b.WriteString(m.styles.DetailValue.Render(m.selectedSecret.Replication))
-	b.WriteString("\n\n")
+	b.WriteString(m.styles.DetailValue.Render(m.replicationSummary()))
+	b.WriteString("\n")
+	
+	for i, line := range m.encryptionLines() {
+		label := ""
+		if i == 0 {
+			label = "Encryption:"
+		}
+		b.WriteString(m.styles.DetailLabel.Render(label))
+		b.WriteString(m.styles.DetailValue.Render(line))
+		b.WriteString("\n")
+	}
+	
+	b.WriteString(m.styles.DetailLabel.Render("Expires:"))
+	b.WriteString(m.expiryDetail())
+	b.WriteString("\n")
+	
+	b.WriteString(m.styles.DetailLabel.Render("Rotation:"))
+	b.WriteString(m.rotationDetail())
+	b.WriteString("\n")
+	
+	b.WriteString(m.styles.DetailLabel.Render("Topics:"))
+	if len(m.selectedSecret.Topics) == 0 {
+		b.WriteString(m.styles.SubtleText().Render("none"))
+	} else {
+		b.WriteString(m.styles.DetailValue.Render(strings.Join(m.selectedSecret.Topics, ", ")))
+	}
+	b.WriteString("\n")
+	
+	b.WriteString(m.styles.DetailLabel.Render("Labels:"))
+	if len(m.selectedSecret.Labels) == 0 {
+		b.WriteString(m.styles.SubtleText().Render("none"))
+	} else {
+		b.WriteString(m.styles.DetailValue.Render(strings.ReplaceAll(gcp.FormatLabels(m.selectedSecret.Labels), ",", ", ")))
+	}
+	b.WriteString("\n")
+	
+	for i, line := range m.annotationLines() {
+		label := ""
+		if i == 0 {
+			label = "Annotations:"
+		}
+		b.WriteString(m.styles.DetailLabel.Render(label))
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 	
 	// Versions
 	b.WriteString(m.styles.ListTitle.Render("Versions"))
@@ -2148,11 +2614,12 @@ func (m Model) viewDetail() string {
 				stateStyle = m.styles.StatusError
 			}
 			
-			line := fmt.Sprintf("%s v%s  %s  %s",
+			line := fmt.Sprintf("%s v%s  %s  %s%s",
 				stateStyle.Render(stateIcon),
 				m.styles.DetailVersion.Render(v.Name),
 				m.styles.SubtleText().Render(v.CreateTime),
 				m.styles.SubtleText().Render(v.State),
+				m.aliasBadges(v),
 			)
 			
 			if i == m.versionCursor {
@@ -2223,7 +2690,7 @@ func (m Model) viewCreate() string {
 	// Location selector
 	b.WriteString(m.styles.InputLabel.Render("Location:"))
 	if m.createFocus == 2 {
-		b.WriteString(m.styles.FooterKey.Render(" (←/→ to navigate, Enter to select)"))
+		b.WriteString(m.styles.FooterKey.Render(" (←/→ to navigate, Space to toggle regions)"))
 	}
 	b.WriteString("\n")
 	
@@ -2238,12 +2705,15 @@ func (m Model) viewCreate() string {
 		
 		// Option 0: Global (automatic replication)
 		globalOption := "🌐 global (automatic replication)"
-		if m.createLocationIdx == 0 {
-			if m.createFocus == 2 {
-				globalOption = m.styles.ListSelected.Render("▶ " + globalOption)
-			} else {
-				globalOption = m.styles.FooterKey.Render("● " + globalOption)
-			}
+		if len(m.createLocations()) == 0 {
+			globalOption = "● " + globalOption
+		} else {
+			globalOption = "○ " + globalOption
+		}
+		if m.createLocationIdx == 0 && m.createFocus == 2 {
+			globalOption = m.styles.ListSelected.Render("▶ " + globalOption)
+		} else if len(m.createLocations()) == 0 {
+			globalOption = m.styles.StatusSuccess.Render("  " + globalOption)
 		} else {
 			globalOption = m.styles.SubtleText().Render("  " + globalOption)
 		}
@@ -2251,13 +2721,14 @@ func (m Model) viewCreate() string {
 		
 		// Options 1..n: Saved locations
 		for i, loc := range m.config.SecretLocations {
-			option := "📍 " + loc
-			if m.createLocationIdx == i+1 { // +1 because 0 is global
-				if m.createFocus == 2 {
-					option = m.styles.ListSelected.Render("▶ " + option)
-				} else {
-					option = m.styles.FooterKey.Render("● " + option)
-				}
+			option := "[ ] 📍 " + loc
+			if m.createRegions[loc] {
+				option = "[x] 📍 " + loc
+			}
+			if m.createLocationIdx == i+1 && m.createFocus == 2 { // +1 because 0 is global
+				option = m.styles.ListSelected.Render("▶ " + option)
+			} else if m.createRegions[loc] {
+				option = m.styles.StatusSuccess.Render("  " + option)
 			} else {
 				option = m.styles.SubtleText().Render("  " + option)
 			}
@@ -2266,12 +2737,8 @@ func (m Model) viewCreate() string {
 		
 		// Option -1: Add new location
 		addNewOption := "➕ Add new location..."
-		if m.createLocationIdx == -1 {
-			if m.createFocus == 2 {
-				addNewOption = m.styles.ListSelected.Render("▶ " + addNewOption)
-			} else {
-				addNewOption = m.styles.FooterKey.Render("● " + addNewOption)
-			}
+		if m.createLocationIdx == -1 && m.createFocus == 2 {
+			addNewOption = m.styles.ListSelected.Render("▶ " + addNewOption)
 		} else {
 			addNewOption = m.styles.SubtleText().Render("  " + addNewOption)
 		}
@@ -2282,8 +2749,19 @@ func (m Model) viewCreate() string {
 			b.WriteString(opt)
 			b.WriteString("\n")
 		}
+		
+		if len(m.createLocations()) > 0 {
+			b.WriteString("\n")
+			b.WriteString(m.viewCreateKMS())
+			b.WriteString("\n")
+		}
 	}
 	
+	b.WriteString("\n")
+	b.WriteString(m.viewCreateExpiry())
+	b.WriteString("\n\n")
+	b.WriteString(m.viewCreateRegional())
+	
 	return m.styles.Dialog.Render(b.String())
 }
 
@@ -2291,7 +2769,7 @@ func (m Model) viewAddVersion() string {
 	var b strings.Builder
 	
 	b.WriteString(m.styles.DialogTitle.Render(
-		fmt.Sprintf("Add Version to %s", m.selectedSecret.Name),
+		fmt.Sprintf("Add Version to %s", m.selectedSecret.ID()),
 	))
 	b.WriteString("\n\n")
 	
@@ -2308,7 +2786,7 @@ func (m Model) viewDelete() string {
 	
 	b.WriteString(m.styles.StatusError.Bold(true).Render("⚠ Delete Secret"))
 	b.WriteString("\n\n")
-	b.WriteString(fmt.Sprintf("Are you sure you want to delete '%s'?\n", m.selectedSecret.Name))
+	b.WriteString(fmt.Sprintf("Are you sure you want to delete '%s'?\n", m.selectedSecret.ID()))
 	b.WriteString(m.styles.SubtleText().Render("This action cannot be undone. All versions will be destroyed."))
 	b.WriteString("\n\n")
 	b.WriteString("Press ")
@@ -2326,6 +2804,13 @@ func (m Model) viewGenerate() string {
 	b.WriteString(m.styles.DialogTitle.Render("Generate Code"))
 	b.WriteString("\n\n")
 	
+	b.WriteString(m.styles.InputLabel.Render("Version: "))
+	b.WriteString(m.styles.DetailVersion.Render(m.templateVersion))
+	if len(m.templateVersions()) > 1 {
+		b.WriteString(m.styles.SubtleText().Render("  (Tab: " + strings.Join(m.templateVersions(), ", ") + ")"))
+	}
+	b.WriteString("\n\n")
+	
 	b.WriteString(m.styles.InputLabel.Render("Select Template:"))
 	b.WriteString("\n\n")
 	
@@ -2442,7 +2927,7 @@ func (m Model) viewConfigTemplates() string {
 	b.WriteString("\n")
 	
 	b.WriteString("\n")
-	b.WriteString(m.styles.SubtleText().Render("Variables: {{.SecretName}}, {{.FullSecretName}}, {{.ProjectID}}"))
+	b.WriteString(m.styles.SubtleText().Render("Variables: {{.SecretName}}, {{.FullSecretName}}, {{.Location}}, {{.ResourceName}}, {{.ProjectID}}"))
 	
 	return b.String()
 }
@@ -2478,7 +2963,7 @@ func (m Model) viewConfigTemplateEdit() string {
 	}
 	b.WriteString(m.styles.InputLabel.Render(codeLabel))
 	b.WriteString("\n")
-	b.WriteString(m.styles.SubtleText().Render("Variables: {{.SecretName}}, {{.FullSecretName}}, {{.ProjectID}}"))
+	b.WriteString(m.styles.SubtleText().Render("Variables: {{.SecretName}}, {{.FullSecretName}}, {{.Location}}, {{.ResourceName}}, {{.ProjectID}}"))
 	b.WriteString("\n")
 	b.WriteString(m.templateCodeArea.View())
 	b.WriteString("\n")
@@ -2852,8 +3337,14 @@ func (m Model) viewFilter() string {
 	b.WriteString("\n\n")
 	
 	// Show preview of filtered results
-	count := len(m.displayItems)
-	b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("%d items matching", count)))
+	if m.filterErr != "" {
+		b.WriteString(m.styles.StatusError.Render("✕ " + m.filterErr))
+	} else {
+		count := len(m.displayItems)
+		b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("%d items matching", count)))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.SubtleText().Render("text • name~db • name=app/db/pass • label:team=payments • env:prod • label:team • -term"))
 	
 	return b.String()
 }
@@ -2868,7 +3359,7 @@ func (m Model) viewReveal() string {
 	// Secret metadata
 	if m.selectedSecret != nil {
 		b.WriteString(m.styles.DetailLabel.Render("Secret: "))
-		b.WriteString(m.styles.DetailValue.Render(m.selectedSecret.Name))
+		b.WriteString(m.styles.DetailValue.Render(m.selectedSecret.ID()))
 		b.WriteString("\n")
 	}
 	b.WriteString(m.styles.DetailLabel.Render("Version: "))
@@ -2965,6 +3456,9 @@ func (m Model) viewReveal() string {
 
 func (m Model) renderBreadcrumb() string {
 	parts := []string{"🏠"}
+	if m.groupBy != "" {
+		parts = []string{"🏷️  " + m.groupBy}
+	}
 	parts = append(parts, m.currentPath...)
 	
 	var rendered []string
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// maxRetryDelay caps the exponential backoff between retries
+const maxRetryDelay = 30 * time.Second
+
+// operation is the work of a command against the secret store, kept so it
+// can run again after a timeout or a transient error
+type operation struct {
+	desc    string // Loading message, defaults to the one on screen
+	bulk    bool   // Many calls, bounded by Esc rather than the API timeout
+	mutates bool   // Repeating it may apply a change twice
+	// Runs while the UI stays usable, so neither Esc nor a retry applies
+	background bool
+	attempt    int
+	run        func(ctx context.Context) (tea.Msg, error)
+}
+
+// opTracker remembers the operation behind the loading splash so Esc can
+// cancel it. Commands are built by value receivers, so every copy of the
+// model shares one tracker.
+type opTracker struct {
+	seq    int
+	id     int
+	cancel context.CancelFunc
+}
+
+// opDoneMsg carries the result message of an operation
+type opDoneMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// opFailedMsg reports an operation that timed out or hit a transient error
+type opFailedMsg struct {
+	id  int
+	op  operation
+	msg tea.Msg // Result delivered as is when the user gives up
+	err error
+}
+
+type opRetryMsg struct {
+	id int
+}
+
+// startOp runs op with its own context, bounded by the configured API
+// timeout. When started behind the loading splash, Esc cancels it and a
+// timeout or transient error offers a retry instead of failing right away.
+func (m Model) startOp(op operation) tea.Cmd {
+	if op.desc == "" {
+		op.desc = m.loadingMsg
+	}
+	foreground := m.loading && !op.background
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout := m.config.API.TimeoutSeconds; timeout > 0 && !op.bulk {
+		ctx, cancel = context.WithTimeout(m.ctx, time.Duration(timeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(m.ctx)
+	}
+
+	m.ops.seq++
+	id := m.ops.seq
+	if foreground {
+		m.ops.id, m.ops.cancel = id, cancel
+	}
+
+	return func() tea.Msg {
+		defer cancel()
+		msg, err := op.run(ctx)
+		cancelled := errors.Is(ctx.Err(), context.Canceled)
+		if err != nil && foreground && !cancelled && gcp.IsTransient(err) {
+			return opFailedMsg{id: id, op: op, msg: msg, err: err}
+		}
+		return opDoneMsg{id: id, msg: msg}
+	}
+}
+
+// endOp forgets the cancel function once its operation has finished
+func (m Model) endOp(id int) {
+	if m.ops.id == id {
+		m.ops.cancel = nil
+	}
+}
+
+// cancelOp cancels the operation behind the loading splash; its result
+// arrives as a cancellation error and is handled like any other failure
+func (m Model) cancelOp() (tea.Model, tea.Cmd) {
+	m.ops.cancel()
+	m.ops.cancel = nil
+	m.loadingMsg = "Cancelling..."
+	return m, nil
+}
+
+func (m Model) handleOpFailed(msg opFailedMsg) (tea.Model, tea.Cmd) {
+	m.endOp(msg.id)
+	m.loading = false
+	m.retry = &msg
+	m.retryWaiting = false
+	return m, nil
+}
+
+// retryDelay is the exponential backoff before the given retry attempt
+func retryDelay(attempt int) time.Duration {
+	delay := time.Second
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+func (m Model) updateRetry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r", "enter":
+		if m.retryWaiting {
+			return m, nil
+		}
+		m.retryWaiting = true
+		id := m.retry.id
+		return m, tea.Tick(retryDelay(m.retry.op.attempt), func(time.Time) tea.Msg {
+			return opRetryMsg{id: id}
+		})
+	case "esc", "n":
+		// Give up and let the usual handler report the failure
+		failed := m.retry
+		m.retry = nil
+		m.retryWaiting = false
+		return m.Update(failed.msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleOpRetry(msg opRetryMsg) (tea.Model, tea.Cmd) {
+	if m.retry == nil || !m.retryWaiting || m.retry.id != msg.id {
+		return m, nil
+	}
+	op := m.retry.op
+	op.attempt++
+	m.retry = nil
+	m.retryWaiting = false
+	m.loading = true
+	m.loadingMsg = op.desc
+	return m, m.startOp(op)
+}
+
+func (m Model) viewRetry() string {
+	var b strings.Builder
+	r := m.retry
+
+	title := "⚠ Request Failed"
+	if gcp.IsTimeout(r.err) {
+		title = "⚠ Request Timed Out"
+	}
+	b.WriteString(m.styles.StatusWarning.Bold(true).Render(title))
+	b.WriteString("\n\n")
+	b.WriteString(strings.TrimSuffix(r.op.desc, "..."))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render(r.err.Error()))
+	b.WriteString("\n\n")
+	if r.op.mutates {
+		b.WriteString(m.styles.StatusWarning.Render("The change may have been applied anyway, check before retrying"))
+		b.WriteString("\n\n")
+	}
+
+	delay := retryDelay(r.op.attempt)
+	if m.retryWaiting {
+		b.WriteString(fmt.Sprintf("⏳ Retrying in %s...", delay))
+		return m.styles.Dialog.Render(b.String())
+	}
+	b.WriteString("Press ")
+	b.WriteString(m.styles.FooterKey.Render("r"))
+	b.WriteString(fmt.Sprintf(" to retry in %s or ", delay))
+	b.WriteString(m.styles.FooterKey.Render("Esc"))
+	b.WriteString(" to give up")
+	if r.op.attempt > 0 {
+		b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf(" (%d retries so far)", r.op.attempt)))
+	}
+
+	return m.styles.Dialog.Render(b.String())
+}
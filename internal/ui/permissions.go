@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+type permissionsTestedMsg struct {
+	projectID  string
+	secretName string // Empty when tested on the project
+	perms      gcp.Permissions
+	err        error
+}
+
+// permissionActions describes what each tested permission allows
+var permissionActions = map[string]string{
+	gcp.PermissionListSecrets:    "list secrets",
+	gcp.PermissionCreateSecret:   "create secrets",
+	gcp.PermissionGetSecret:      "view secret metadata",
+	gcp.PermissionUpdateSecret:   "edit labels and other metadata",
+	gcp.PermissionDeleteSecret:   "delete secrets",
+	gcp.PermissionListVersions:   "list versions",
+	gcp.PermissionAccessVersion:  "reveal and copy values",
+	gcp.PermissionAddVersion:     "add versions",
+	gcp.PermissionEnableVersion:  "enable versions",
+	gcp.PermissionDisableVersion: "disable versions",
+	gcp.PermissionDestroyVersion: "destroy versions",
+	gcp.PermissionGetIamPolicy:   "view the IAM policy",
+	gcp.PermissionSetIamPolicy:   "grant and revoke access",
+}
+
+// Permissions needed by the keys of the list view, the detail view and
+// its IAM tab
+var (
+	listPermissions = map[string]string{
+		"n": gcp.PermissionCreateSecret,
+		"d": gcp.PermissionDeleteSecret,
+	}
+	detailPermissions = map[string]string{
+		"r":   gcp.PermissionAccessVersion,
+		"c":   gcp.PermissionAccessVersion,
+		"y":   gcp.PermissionAccessVersion,
+		"a":   gcp.PermissionAddVersion,
+		"x":   gcp.PermissionDisableVersion,
+		"e":   gcp.PermissionEnableVersion,
+		"X":   gcp.PermissionDestroyVersion,
+		"L":   gcp.PermissionUpdateSecret,
+		"N":   gcp.PermissionUpdateSecret,
+		"t":   gcp.PermissionUpdateSecret,
+		"R":   gcp.PermissionUpdateSecret,
+		"A":   gcp.PermissionUpdateSecret,
+		"d":   gcp.PermissionDeleteSecret,
+		"tab": gcp.PermissionGetIamPolicy,
+	}
+	iamPermissions = map[string]string{
+		"a": gcp.PermissionSetIamPolicy,
+		"x": gcp.PermissionSetIamPolicy,
+	}
+)
+
+// testPermissions checks in the background which Secret Manager permissions
+// the caller holds on a secret, or on the project when secretName is empty
+func (m Model) testPermissions(secretName string) tea.Cmd {
+	projectID := m.config.ProjectID
+	tested := gcp.ProjectPermissions
+	if secretName != "" {
+		tested = gcp.SecretPermissions
+	}
+	return m.startOp(operation{background: true, run: func(ctx context.Context) (tea.Msg, error) {
+		msg := permissionsTestedMsg{projectID: projectID, secretName: secretName}
+		var granted []string
+		granted, msg.err = m.client.TestIamPermissions(ctx, secretName, tested)
+		if msg.err == nil {
+			msg.perms = gcp.NewPermissions(tested, granted)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) handlePermissionsTested(msg permissionsTestedMsg) (tea.Model, tea.Cmd) {
+	if msg.projectID != m.config.ProjectID {
+		return m, nil
+	}
+	if msg.secretName == "" {
+		m.projectPerms, m.projectPermsErr = msg.perms, msg.err
+		return m, nil
+	}
+	m.secretPermsFor = msg.secretName
+	m.secretPerms, m.secretPermsErr = msg.perms, msg.err
+	return m, nil
+}
+
+// allows reports whether the caller may use permission on the selected
+// secret, or on the project when no secret is selected. Permissions that
+// could not be tested are allowed.
+func (m Model) allows(permission string) bool {
+	if m.selectedSecret != nil && m.secretPermsFor == m.selectedSecret.ID() && m.secretPerms != nil {
+		return m.secretPerms.Allows(permission)
+	}
+	return m.projectPerms.Allows(permission)
+}
+
+// checkPermission reports whether key may be used, explaining in the
+// status bar which permission is missing when it may not
+func (m *Model) checkPermission(permissions map[string]string, key string) bool {
+	permission, ok := permissions[key]
+	if !ok || m.allows(permission) {
+		return true
+	}
+	m.statusMsg = fmt.Sprintf("Cannot %s: missing permission %s", permissionActions[permission], permission)
+	m.statusErr = true
+	return false
+}
+
+// greyOut disables the footer bindings whose keys all need a missing permission
+func (m Model) greyOut(bindings []FooterBinding, permissions map[string]string) []FooterBinding {
+	for i, binding := range bindings {
+		denied := false
+		for _, key := range strings.Split(binding.Key, "/") {
+			if len(key) > 1 {
+				key = strings.ToLower(key)
+			}
+			permission, ok := permissions[key]
+			if !ok || m.allows(permission) {
+				denied = false
+				break
+			}
+			denied = true
+		}
+		bindings[i].Disabled = denied
+	}
+	return bindings
+}
+
+// openPermissions shows the permissions panel and tests the permissions
+// again, as roles may have changed since they were last tested
+func (m Model) openPermissions() (tea.Model, tea.Cmd) {
+	m.permissionsPrevView = m.view
+	m.view = ViewPermissions
+	return m, m.retestPermissions()
+}
+
+func (m *Model) retestPermissions() tea.Cmd {
+	m.projectPerms, m.projectPermsErr = nil, nil
+	cmds := []tea.Cmd{m.testPermissions("")}
+	if m.permissionsPrevView == ViewDetail && m.selectedSecret != nil {
+		m.secretPermsFor = m.selectedSecret.ID()
+		m.secretPerms, m.secretPermsErr = nil, nil
+		cmds = append(cmds, m.testPermissions(m.secretPermsFor))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) updatePermissions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+r":
+		return m, m.retestPermissions()
+	case "esc", "P", "q":
+		m.view = m.permissionsPrevView
+	}
+	return m, nil
+}
+
+func (m Model) viewPermissions() string {
+	var b strings.Builder
+
+	identity := m.client.Principal()
+	if identity == "" {
+		identity = "the current credentials"
+	}
+	b.WriteString(m.styles.DialogTitle.Render(fmt.Sprintf("Permissions of %s", identity)))
+	b.WriteString("\n\n")
+
+	secret := ""
+	if m.permissionsPrevView == ViewDetail && m.selectedSecret != nil {
+		secret = m.selectedSecret.ID()
+	}
+
+	header := fmt.Sprintf("%-8s", "Project")
+	if secret != "" {
+		header += fmt.Sprintf("%-8s", "Secret")
+	}
+	b.WriteString(m.styles.InputLabel.Render(header + "Action"))
+	b.WriteString("\n")
+	for _, permission := range gcp.ProjectPermissions {
+		line := m.permissionMark(m.projectPerms, m.projectPermsErr, permission)
+		if secret != "" {
+			if permission == gcp.PermissionListSecrets || permission == gcp.PermissionCreateSecret {
+				line += fmt.Sprintf("%-8s", "")
+			} else {
+				line += m.permissionMark(m.secretPerms, m.secretPermsErr, permission)
+			}
+		}
+		b.WriteString(line)
+		b.WriteString(m.styles.DetailValue.Render(fmt.Sprintf("%-32s", permissionActions[permission])))
+		b.WriteString(m.styles.SubtleText().Render(permission))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf("Project: %s", m.config.ProjectID)))
+	if secret != "" {
+		b.WriteString(m.styles.SubtleText().Render(fmt.Sprintf(", secret: %s (including roles granted on the secret)", secret)))
+	}
+	if m.projectPermsErr != nil {
+		b.WriteString("\n")
+		b.WriteString(m.styles.StatusError.Render(fmt.Sprintf("Could not test project permissions: %v", m.projectPermsErr)))
+	}
+	if secret != "" && m.secretPermsErr != nil {
+		b.WriteString("\n")
+		b.WriteString(m.styles.StatusError.Render(fmt.Sprintf("Could not test secret permissions: %v", m.secretPermsErr)))
+	}
+
+	return m.styles.Dialog.Render(b.String())
+}
+
+// permissionMark renders a column of the permissions panel
+func (m Model) permissionMark(perms gcp.Permissions, err error, permission string) string {
+	switch {
+	case err != nil:
+		return m.styles.SubtleText().Render(fmt.Sprintf("%-8s", "?"))
+	case perms == nil:
+		return m.styles.SubtleText().Render(fmt.Sprintf("%-8s", "…"))
+	case perms.Allows(permission):
+		return m.styles.StatusSuccess.Render(fmt.Sprintf("%-8s", "✓"))
+	default:
+		return m.styles.StatusError.Render(fmt.Sprintf("%-8s", "✕"))
+	}
+}
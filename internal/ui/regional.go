@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// listLocations returns the locations a listing covers: global secrets
+// first, then the regional secrets of every configured location
+func (m Model) listLocations() []string {
+	return append([]string{""}, m.config.SecretLocations...)
+}
+
+// locationName renders the location of a secret, global when it has none
+func locationName(location string) string {
+	if location == "" {
+		return "global"
+	}
+	return location
+}
+
+// locationColumn renders the location of the secret behind a list item
+func (m Model) locationColumn(item *FolderItem) string {
+	if item.Secret == nil || item.Secret.Location == "" {
+		return m.styles.SubtleText().Render(locationName(""))
+	}
+	return m.styles.StatusWarning.Render("📍 " + item.Secret.Location)
+}
+
+// createRegionalField is the index of the selector of the create dialog
+// choosing between a global secret and a regional one, after the expiry
+func (m Model) createRegionalField() int {
+	return m.createExpiryField() + 1
+}
+
+// moveCreateRegional moves the regional location of the secret being
+// created through global and the configured locations. A regional secret
+// lives in its location only, so it drops the selected replica locations.
+func (m *Model) moveCreateRegional(delta int) {
+	options := m.listLocations()
+	current := 0
+	for i, location := range options {
+		if location == m.createRegional {
+			current = i
+		}
+	}
+	m.createRegional = options[(current+delta+len(options))%len(options)]
+	if m.createRegional != "" && len(m.createRegions) > 0 {
+		m.createRegions = make(map[string]bool)
+		m.syncCreateKMS()
+	}
+	// Dropping replicas removes their KMS key fields, which precede this one
+	m.createFocus = m.createRegionalField()
+}
+
+// createSpec returns the secret the create dialog describes: a regional
+// secret encrypted with the key saved for its location, or a global secret
+// replicated to the selected locations
+func (m *Model) createSpec(name string, expireTime time.Time) (gcp.Secret, error) {
+	spec := gcp.Secret{Name: name, Location: m.createRegional, ExpireTime: expireTime}
+	if spec.Location == "" {
+		replicas, err := m.createReplicas()
+		spec.Replicas = replicas
+		return spec, err
+	}
+	spec.KMSKeyName = m.config.SecretKMSKey(spec.Location)
+	if spec.KMSKeyName != "" {
+		if err := gcp.ValidateKMSKeyName(spec.KMSKeyName, spec.Location); err != nil {
+			return gcp.Secret{}, err
+		}
+	}
+	return spec, nil
+}
+
+func (m Model) viewCreateRegional() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.InputLabel.Render("Stored in:"))
+	focused := m.createFocus == m.createRegionalField()
+	if focused {
+		b.WriteString(m.styles.FooterKey.Render(" (←/→ to choose)"))
+	}
+	if len(m.config.SecretLocations) == 0 {
+		b.WriteString(m.styles.SubtleText().Render("  add a location to create regional secrets"))
+	}
+	b.WriteString("\n")
+
+	for _, location := range m.listLocations() {
+		option := "○ " + locationName(location)
+		if location == m.createRegional {
+			option = "● " + locationName(location)
+		}
+		switch {
+		case location == m.createRegional && focused:
+			b.WriteString(m.styles.ListSelected.Render("▶ " + option))
+		case location == m.createRegional:
+			b.WriteString(m.styles.StatusSuccess.Render("  " + option))
+		default:
+			b.WriteString(m.styles.SubtleText().Render("  " + option))
+		}
+	}
+	if m.createRegional != "" {
+		b.WriteString("\n")
+		b.WriteString(m.styles.SubtleText().Render("Regional secret, kept in " + m.createRegional + " only"))
+		if key := m.config.SecretKMSKey(m.createRegional); key != "" {
+			b.WriteString("\n")
+			b.WriteString(m.styles.SubtleText().Render("Encrypted with " + key))
+		}
+	}
+	return b.String()
+}
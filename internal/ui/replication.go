@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// resetCreateLocations preselects the first saved location for a new secret,
+// or automatic replication when there is none
+func (m *Model) resetCreateLocations() {
+	m.createRegional = ""
+	m.createRegions = make(map[string]bool)
+	m.createLocationIdx = 0
+	if len(m.config.SecretLocations) > 0 {
+		m.createLocationIdx = 1
+		m.createRegions[m.config.SecretLocations[0]] = true
+	}
+	m.syncCreateKMS()
+}
+
+// createLocations returns the replica locations selected in the create
+// dialog in the order of the saved locations; none means automatic replication
+func (m Model) createLocations() []string {
+	var locations []string
+	for _, location := range m.config.SecretLocations {
+		if m.createRegions[location] {
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
+// toggleCreateLocation toggles the location under the cursor. Picking global
+// clears the selected regions, picking a region makes the secret global.
+func (m *Model) toggleCreateLocation() {
+	switch {
+	case m.createLocationIdx == 0:
+		m.createRegions = make(map[string]bool)
+	case m.createLocationIdx > 0 && m.createLocationIdx <= len(m.config.SecretLocations):
+		location := m.config.SecretLocations[m.createLocationIdx-1]
+		if m.createRegions[location] {
+			delete(m.createRegions, location)
+		} else {
+			m.createRegions[location] = true
+			m.createRegional = ""
+		}
+	}
+	m.syncCreateKMS()
+}
+
+// replicationSummary describes the replication of the selected secret,
+// listing every replica location of user-managed replication
+func (m Model) replicationSummary() string {
+	secret := m.selectedSecret
+	if len(secret.Replicas) == 0 {
+		return secret.Replication
+	}
+	locations := make([]string, 0, len(secret.Replicas))
+	for _, replica := range secret.Replicas {
+		locations = append(locations, replica.Location)
+	}
+	return fmt.Sprintf("%s (%s)", secret.Replication, strings.Join(locations, ", "))
+}
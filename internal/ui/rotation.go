@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+)
+
+// Fields of the rotation dialog
+const (
+	rotationPeriodField = iota
+	rotationNextField
+	rotationTopicsField
+	rotationFields
+)
+
+type rotationUpdatedMsg struct {
+	secretName  string
+	oldRotation string // Described by describeRotation, for the audit log
+	rotation    gcp.Rotation
+	topics      []string
+	secret      *gcp.Secret // Secret as stored after the update or the conflict
+	err         error
+}
+
+// describeRotation renders a rotation schedule with its topics for the audit log
+func describeRotation(rotation gcp.Rotation, topics []string) string {
+	if len(topics) == 0 {
+		return rotation.String()
+	}
+	return fmt.Sprintf("%s; topics: %s", rotation, strings.Join(topics, ", "))
+}
+
+// rotationOverdue reports whether the next rotation of a secret has passed
+func rotationOverdue(secret *gcp.Secret) bool {
+	return secret.Rotation.Overdue(time.Now())
+}
+
+// rotationColumn renders a badge for the secret behind a list item when its
+// rotation is overdue
+func (m Model) rotationColumn(item *FolderItem) string {
+	if item.Secret == nil || !rotationOverdue(item.Secret) {
+		return ""
+	}
+	return m.styles.StatusWarning.Render("↻ rotation overdue")
+}
+
+// rotationDetail renders the rotation schedule of the selected secret for
+// the detail view, highlighted when overdue
+func (m Model) rotationDetail() string {
+	rotation := m.selectedSecret.Rotation
+	if rotation.IsZero() {
+		return m.styles.SubtleText().Render("none")
+	}
+	if rotationOverdue(m.selectedSecret) {
+		return m.styles.StatusWarning.Render(fmt.Sprintf("⚠ %s (overdue by %s)",
+			rotation, gcp.FormatRemaining(time.Now(), rotation.NextRotationTime)))
+	}
+	return m.styles.DetailValue.Render(fmt.Sprintf("%s (in %s)",
+		rotation, gcp.FormatRemaining(rotation.NextRotationTime, time.Now())))
+}
+
+// openRotation shows the dialog editing the rotation schedule and topics of
+// the selected secret
+func (m Model) openRotation() (tea.Model, tea.Cmd) {
+	if m.selectedSecret == nil {
+		return m, nil
+	}
+	secret := m.selectedSecret
+
+	period := textinput.New()
+	period.Placeholder = "30d, empty for a single rotation"
+	period.CharLimit = 20
+	period.Width = 46
+	if secret.Rotation.Period != 0 {
+		period.SetValue(gcp.FormatPeriod(secret.Rotation.Period))
+	}
+
+	next := textinput.New()
+	next.Placeholder = "7d, 2030-01-31, empty for one period from now"
+	next.CharLimit = 40
+	next.Width = 46
+	next.SetValue(expiryInputValue(secret.Rotation.NextRotationTime))
+
+	topics := textinput.New()
+	topics.Placeholder = "projects/P/topics/T, comma separated"
+	topics.CharLimit = 1000
+	topics.Width = 46
+	topics.SetValue(strings.Join(secret.Topics, ", "))
+
+	m.rotationInputs = []textinput.Model{period, next, topics}
+	m.rotationFocus = rotationPeriodField
+	m.rotationInputs[m.rotationFocus].Focus()
+	m.view = ViewRotation
+	return m, textinput.Blink
+}
+
+func (m Model) updateRotation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down", "shift+tab", "up":
+		m.rotationInputs[m.rotationFocus].Blur()
+		if msg.String() == "tab" || msg.String() == "down" {
+			m.rotationFocus = (m.rotationFocus + 1) % rotationFields
+		} else {
+			m.rotationFocus = (m.rotationFocus + rotationFields - 1) % rotationFields
+		}
+		m.rotationInputs[m.rotationFocus].Focus()
+		return m, textinput.Blink
+	case "ctrl+d":
+		// Clear the schedule, keeping the topics for other notifications
+		m.rotationInputs[rotationPeriodField].SetValue("")
+		m.rotationInputs[rotationNextField].SetValue("")
+		return m, nil
+	case "enter":
+		rotation, topics, err := m.rotationFromInputs(time.Now())
+		if err != nil {
+			m.statusMsg = err.Error()
+			m.statusErr = true
+			return m, nil
+		}
+		secret := *m.selectedSecret
+		if sameRotation(secret.Rotation, rotation) && strings.Join(secret.Topics, ",") == strings.Join(topics, ",") {
+			m.rotationInputs = nil
+			m.view = ViewDetail
+			return m, nil
+		}
+		m.loading = true
+		m.loadingMsg = "Updating rotation..."
+		return m, m.updateRotationSettings(secret, rotation, topics)
+	case "esc":
+		m.rotationInputs = nil
+		m.view = ViewDetail
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.rotationInputs[m.rotationFocus], cmd = m.rotationInputs[m.rotationFocus].Update(msg)
+	return m, cmd
+}
+
+// sameRotation reports whether two rotation schedules are identical
+func sameRotation(a, b gcp.Rotation) bool {
+	return a.Period == b.Period && a.NextRotationTime.Equal(b.NextRotationTime)
+}
+
+// rotationFromInputs parses and validates the rotation dialog. Without a
+// next rotation time, the first rotation happens one period from now.
+func (m Model) rotationFromInputs(now time.Time) (gcp.Rotation, []string, error) {
+	var rotation gcp.Rotation
+	if value := strings.TrimSpace(m.rotationInputs[rotationPeriodField].Value()); value != "" {
+		period, err := gcp.ParseTTL(value)
+		if err != nil {
+			return rotation, nil, err
+		}
+		rotation.Period = period
+		rotation.NextRotationTime = now.Add(period).UTC()
+	}
+	value := strings.TrimSpace(m.rotationInputs[rotationNextField].Value())
+	if value != "" && value != expiryInputValue(m.selectedSecret.Rotation.NextRotationTime) {
+		next, err := gcp.ParseExpiry(value, now)
+		if err != nil {
+			return rotation, nil, err
+		}
+		rotation.NextRotationTime = next
+	} else if value != "" && !m.selectedSecret.Rotation.NextRotationTime.IsZero() {
+		// Unchanged, even if already overdue
+		rotation.NextRotationTime = m.selectedSecret.Rotation.NextRotationTime
+	}
+
+	var topics []string
+	for _, topic := range strings.Split(m.rotationInputs[rotationTopicsField].Value(), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	if err := gcp.ValidateRotation(rotation, topics); err != nil {
+		return rotation, nil, err
+	}
+	return rotation, topics, nil
+}
+
+func (m Model) updateRotationSettings(secret gcp.Secret, rotation gcp.Rotation, topics []string) tea.Cmd {
+	secretName := secret.ID()
+	oldRotation := describeRotation(secret.Rotation, secret.Topics)
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		msg := rotationUpdatedMsg{secretName: secretName, oldRotation: oldRotation, rotation: rotation, topics: topics}
+		msg.err = m.client.UpdateSecretRotation(ctx, secretName, rotation, topics, secret.Etag)
+		if msg.err == nil || gcp.IsConflict(msg.err) {
+			// Pick up the new etag, or what someone else changed
+			msg.secret, _ = m.client.GetSecret(ctx, secretName)
+		}
+		return msg, msg.err
+	}})
+}
+
+func (m Model) handleRotationUpdated(msg rotationUpdatedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		m.auditLogger.LogRotationUpdate(m.config.ProjectID, msg.secretName,
+			msg.oldRotation, describeRotation(msg.rotation, msg.topics), result, errMsg)
+	}
+
+	if m.selectedSecret != nil && m.selectedSecret.ID() == msg.secretName {
+		if msg.err == nil {
+			m.selectedSecret.Rotation = msg.rotation
+			m.selectedSecret.Topics = msg.topics
+			m.selectedSecret.Etag = ""
+		}
+		if msg.secret != nil {
+			m.selectedSecret.Rotation = msg.secret.Rotation
+			m.selectedSecret.Topics = msg.secret.Topics
+			m.selectedSecret.Etag = msg.secret.Etag
+		}
+	}
+
+	if msg.err != nil {
+		if gcp.IsConflict(msg.err) && msg.secret != nil {
+			// Stay in the dialog so the edit can be applied to the reloaded secret
+			m.statusMsg = fmt.Sprintf("Secret was changed by someone else, rotation is now: %s. Press Enter to apply anyway",
+				describeRotation(msg.secret.Rotation, msg.secret.Topics))
+		} else {
+			m.statusMsg = fmt.Sprintf("Error updating rotation: %v", msg.err)
+		}
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.rotationInputs = nil
+	m.view = ViewDetail
+	m.statusMsg = "✓ Rotation removed"
+	if !msg.rotation.IsZero() {
+		m.statusMsg = "✓ Rotation " + msg.rotation.String()
+	}
+	m.statusErr = false
+	return m, nil
+}
+
+func (m Model) viewRotation() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.DialogTitle.Render("Rotation of " + m.selectedSecret.ID()))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.InputLabel.Render("Currently: "))
+	b.WriteString(m.rotationDetail())
+	b.WriteString("\n\n")
+
+	labels := []string{"Rotation period:", "Next rotation:", "Topics:"}
+	for i, label := range labels {
+		b.WriteString(m.styles.InputLabel.Render(label))
+		b.WriteString("\n")
+		inputStyle := m.styles.Input
+		if i == m.rotationFocus {
+			inputStyle = m.styles.InputFocused
+		}
+		b.WriteString(inputStyle.Width(50).Render(m.rotationInputs[i].View()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Secret Manager publishes SECRET_ROTATE to the topics at each rotation time"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.SubtleText().Render("Clear period and next rotation to stop rotating; a rotation needs a topic"))
+
+	return m.styles.Dialog.Render(b.String())
+}
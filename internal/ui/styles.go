@@ -55,6 +55,7 @@ type Styles struct {
 	DetailValue   lipgloss.Style
 	DetailVersion lipgloss.Style
 	DetailSecret  lipgloss.Style
+	DetailLink    lipgloss.Style
 	
 	// Input styles
 	Input        lipgloss.Style
@@ -158,6 +159,10 @@ func NewStyles() *Styles {
 		DetailSecret: lipgloss.NewStyle().
 			Foreground(ColorGreen),
 		
+		DetailLink: lipgloss.NewStyle().
+			Foreground(ColorCyan).
+			Underline(true),
+		
 		Input: lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
 			BorderForeground(ColorBorder).
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/audit"
+)
+
+// versionAction is a state change of a secret version
+type versionAction int
+
+const (
+	versionDisable versionAction = iota
+	versionEnable
+	versionDestroy
+)
+
+func (a versionAction) verb() string {
+	switch a {
+	case versionDisable:
+		return "disable"
+	case versionEnable:
+		return "enable"
+	default:
+		return "destroy"
+	}
+}
+
+func (a versionAction) past() string {
+	switch a {
+	case versionDisable:
+		return "disabled"
+	case versionEnable:
+		return "enabled"
+	default:
+		return "destroyed"
+	}
+}
+
+type versionStateChangedMsg struct {
+	action     versionAction
+	secretName string
+	version    string
+	err        error
+}
+
+// openVersionAction asks for confirmation of a state change of the selected version
+func (m Model) openVersionAction(action versionAction) (tea.Model, tea.Cmd) {
+	if len(m.versions) == 0 {
+		return m, nil
+	}
+	version := m.versions[m.versionCursor]
+
+	var reason string
+	switch {
+	case version.State == "DESTROYED":
+		reason = "already destroyed"
+	case action == versionDisable && version.State == "DISABLED":
+		reason = "already disabled"
+	case action == versionEnable && version.State == "ENABLED":
+		reason = "already enabled"
+	}
+	if reason != "" {
+		m.statusMsg = fmt.Sprintf("Version %s is %s", version.Name, reason)
+		m.statusErr = true
+		return m, nil
+	}
+
+	m.versionAction = action
+	m.versionActionTarget = version.Name
+	m.view = ViewVersionAction
+	if action == versionDestroy {
+		m.destroyInput = textinput.New()
+		m.destroyInput.CharLimit = 20
+		m.destroyInput.Width = 20
+		m.destroyInput.Focus()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+func (m Model) updateVersionAction(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		return m, nil
+	}
+
+	if m.versionAction == versionDestroy {
+		switch msg.String() {
+		case "enter":
+			if strings.TrimSpace(m.destroyInput.Value()) != m.versionActionTarget {
+				m.statusMsg = fmt.Sprintf("Type %s to destroy this version", m.versionActionTarget)
+				m.statusErr = true
+				return m, nil
+			}
+			m.destroyInput.Blur()
+			m.loading = true
+			m.loadingMsg = "Destroying version..."
+			return m, m.changeVersionState(m.versionAction, m.selectedSecret.ID(), m.versionActionTarget)
+		case "esc":
+			m.destroyInput.Blur()
+			m.view = ViewDetail
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.destroyInput, cmd = m.destroyInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "y":
+		m.loading = true
+		if m.versionAction == versionDisable {
+			m.loadingMsg = "Disabling version..."
+		} else {
+			m.loadingMsg = "Enabling version..."
+		}
+		return m, m.changeVersionState(m.versionAction, m.selectedSecret.ID(), m.versionActionTarget)
+	case "n", "esc":
+		m.view = ViewDetail
+	}
+	return m, nil
+}
+
+func (m Model) changeVersionState(action versionAction, secretName, version string) tea.Cmd {
+	return m.startOp(operation{run: func(ctx context.Context) (tea.Msg, error) {
+		var err error
+		switch action {
+		case versionDisable:
+			err = m.client.DisableSecretVersion(ctx, secretName, version)
+		case versionEnable:
+			err = m.client.EnableSecretVersion(ctx, secretName, version)
+		case versionDestroy:
+			err = m.client.DestroySecretVersion(ctx, secretName, version)
+		}
+		return versionStateChangedMsg{action: action, secretName: secretName, version: version, err: err}, err
+	}})
+}
+
+func (m Model) handleVersionStateChanged(msg versionStateChangedMsg) (tea.Model, tea.Cmd) {
+	result, errMsg := audit.ResultSuccess, ""
+	if msg.err != nil {
+		result, errMsg = audit.ResultFailure, msg.err.Error()
+	}
+	if m.auditLogger != nil {
+		switch msg.action {
+		case versionDisable:
+			m.auditLogger.LogVersionDisable(m.config.ProjectID, msg.secretName, msg.version, result, errMsg)
+		case versionEnable:
+			m.auditLogger.LogVersionEnable(m.config.ProjectID, msg.secretName, msg.version, result, errMsg)
+		case versionDestroy:
+			m.auditLogger.LogVersionDestroy(m.config.ProjectID, msg.secretName, msg.version, result, errMsg)
+		}
+	}
+
+	m.view = ViewDetail
+	if msg.err != nil {
+		m.loading = false
+		m.statusMsg = fmt.Sprintf("Error: could not %s version %s: %v", msg.action.verb(), msg.version, msg.err)
+		m.statusErr = true
+		return m, nil
+	}
+	m.statusMsg = fmt.Sprintf("✓ Version %s %s", msg.version, msg.action.past())
+	m.statusErr = false
+	m.loadingMsg = "Loading versions..."
+	return m, m.loadVersions(msg.secretName)
+}
+
+func (m Model) viewVersionAction() string {
+	if m.loading {
+		return m.viewSplash(m.loadingMsg, "⏳", "")
+	}
+
+	var b strings.Builder
+	name := m.selectedSecret.ID()
+
+	switch m.versionAction {
+	case versionDestroy:
+		b.WriteString(m.styles.StatusError.Bold(true).Render("⚠ Destroy Version"))
+		b.WriteString("\n\n")
+		b.WriteString(fmt.Sprintf("Destroy version %s of '%s'?\n", m.versionActionTarget, name))
+		b.WriteString(m.styles.SubtleText().Render("The payload is erased permanently. This action cannot be undone."))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.InputLabel.Render(fmt.Sprintf("Type %s to confirm:", m.versionActionTarget)))
+		b.WriteString("\n")
+		b.WriteString(m.styles.InputFocused.Width(24).Render(m.destroyInput.View()))
+		return m.styles.Dialog.Render(b.String())
+	case versionDisable:
+		b.WriteString(m.styles.StatusWarning.Bold(true).Render("Disable Version"))
+		b.WriteString("\n\n")
+		b.WriteString(fmt.Sprintf("Disable version %s of '%s'?\n", m.versionActionTarget, name))
+		b.WriteString(m.styles.SubtleText().Render("Disabled versions cannot be accessed until enabled again."))
+	default:
+		b.WriteString(m.styles.DialogTitle.Render("Enable Version"))
+		b.WriteString("\n\n")
+		b.WriteString(fmt.Sprintf("Enable version %s of '%s'?\n", m.versionActionTarget, name))
+		b.WriteString(m.styles.SubtleText().Render("The version becomes accessible again."))
+	}
+	b.WriteString("\n\n")
+	b.WriteString("Press ")
+	b.WriteString(m.styles.FooterKey.Render("y"))
+	b.WriteString(" to confirm or ")
+	b.WriteString(m.styles.FooterKey.Render("n"))
+	b.WriteString(" to cancel")
+
+	return m.styles.Dialog.Render(b.String())
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/theburrowhub/go-secret/internal/cli"
 	"github.com/theburrowhub/go-secret/internal/config"
+	"github.com/theburrowhub/go-secret/internal/gcp"
+	"github.com/theburrowhub/go-secret/internal/memstore"
 	"github.com/theburrowhub/go-secret/internal/ui"
 )
 
@@ -14,6 +18,17 @@ func main() {
 	// Parse command line flags
 	projectID := flag.String("project", "", "GCP Project ID")
 	flag.StringVar(projectID, "p", "", "GCP Project ID (shorthand)")
+	backend := flag.String("backend", "gcp", "Secret backend: gcp or memory")
+	demo := flag.Bool("demo", false, "Use built-in demo data in memory (same as -backend=memory)")
+	fixture := flag.String("fixture", "", "YAML fixture to seed the memory backend (implies -backend=memory)")
+	var creds config.Credentials
+	flag.StringVar(&creds.File, "credentials-file", "", "Service account key or external account JSON to use instead of Application Default Credentials")
+	flag.StringVar(&creds.ImpersonateServiceAccount, "impersonate-service-account", "", "Service account email to act as in every project")
+	flag.Usage = func() {
+		cli.PrintUsage(flag.CommandLine.Output())
+		fmt.Fprintln(flag.CommandLine.Output(), "\nGlobal flags:")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	// Load configuration
@@ -23,8 +38,30 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *demo || *fixture != "" {
+		*backend = "memory"
+	}
+	openStore, err := selectBackend(cfg, *backend, *fixture, *projectID, creds)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Run a subcommand instead of the TUI when one is given
+	if flag.NArg() > 0 {
+		env := cli.Env{
+			Config:    cfg,
+			OpenStore: openStore,
+			ProjectID: *projectID,
+			Stdin:     os.Stdin,
+			Stdout:    os.Stdout,
+			Stderr:    os.Stderr,
+		}
+		os.Exit(cli.Run(context.Background(), env, flag.Args()))
+	}
+
 	// Create the model
-	model := ui.NewModel(cfg, *projectID)
+	model := ui.NewModel(cfg, *projectID, openStore)
 
 	// Create and run the program
 	p := tea.NewProgram(
@@ -39,3 +76,58 @@ func main() {
 	}
 }
 
+// selectBackend returns the store opener for the named backend.
+// The memory backend switches cfg to its fixture projects and never saves it,
+// so a demo session leaves the real configuration untouched.
+func selectBackend(cfg *config.Config, name, fixturePath, projectID string, creds config.Credentials) (gcp.StoreOpener, error) {
+	switch name {
+	case "gcp":
+		return gcp.OpenStoreWith(func(projectID string) gcp.Credentials {
+			return credentialsFor(cfg, projectID, creds)
+		}), nil
+	case "memory":
+		if creds != (config.Credentials{}) {
+			return nil, fmt.Errorf("-credentials-file and -impersonate-service-account need the gcp backend")
+		}
+		var f *memstore.Fixture
+		var err error
+		if fixturePath != "" {
+			f, err = memstore.LoadFixture(fixturePath)
+		} else {
+			f, err = memstore.DemoFixture()
+		}
+		if err != nil {
+			return nil, err
+		}
+		backend, err := memstore.New(f)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.DisablePersistence()
+		cfg.RecentProjects = backend.Projects()
+		cfg.SecretLocations = backend.Locations()
+		if projectID == "" {
+			cfg.ProjectID = f.DefaultProject
+		}
+		return backend.Open, nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q (expected gcp or memory)", name)
+	}
+}
+
+// credentialsFor returns the credentials of a project: those configured for
+// it, with each field set on the command line taking precedence
+func credentialsFor(cfg *config.Config, projectID string, flags config.Credentials) gcp.Credentials {
+	creds := cfg.CredentialsFor(projectID)
+	if flags.File != "" {
+		creds.File = flags.File
+	}
+	if flags.ImpersonateServiceAccount != "" {
+		creds.ImpersonateServiceAccount = flags.ImpersonateServiceAccount
+	}
+	return gcp.Credentials{
+		File:                      creds.File,
+		ImpersonateServiceAccount: creds.ImpersonateServiceAccount,
+	}
+}